@@ -1,19 +1,195 @@
 package container
 
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
 type AppContainer interface {
 	Container
 
+	// Close shuts down all started modules with ShutdownAll, so the container can be used as an io.Closer.
+	io.Closer
+
 	// SetModuleGlobalConfig adds static configurations of modules in to the container.
 	SetModuleGlobalConfig(configs ...ModuleConfig) error
 
-	// Start starts modules iteratively in the order they are provided.
+	// ConfigSnapshot returns a deep copy of every global config, with sensitive fields masked.
+	ConfigSnapshot() map[string]any
+
+	// ReloadConfig loads the given global configs again, or all of them, and notifies the modules whose config changed.
+	ReloadConfig(keys ...string) error
+
+	// Start starts modules iteratively in the order they are provided,
+	// with each module started after the modules it depends on.
 	//
 	// This is done by invoking the Run() method of each module.
 	// Before Run() is called readiness of each module is verified using Ready().
 	Start(modules ...string)
 
+	// StartE starts modules like Start but returns failures instead of panicking.
+	StartE(modules ...string) error
+
+	// StartAll starts every enabled runnable module, except those bound with SkipAutoStart, in dependency order.
+	StartAll() error
+
+	// FinalizeOnPanic runs the finalizers if the deferring goroutine panics, then lets the panic continue.
+	FinalizeOnPanic()
+
+	// RegisterStopSignal registers channels that each trigger a graceful shutdown when they fire.
+	RegisterStopSignal(chs ...<-chan any)
+
+	// RegisterAllStopSignal registers channels that trigger a graceful shutdown once all of them have fired.
+	RegisterAllStopSignal(chs ...<-chan any)
+
+	// StartRace starts modules, stops the others once the first Run returns and reports which finished first.
+	StartRace(modules ...string) (winner string, err error)
+
+	// RunToCompletion starts modules and waits for the Run of every module to return, for batch jobs.
+	RunToCompletion(modules ...string) error
+
+	// StartFromConfig starts the modules listed in the global config registered under key.
+	StartFromConfig(key string) error
+
+	// UseRunMiddleware registers middleware wrapping the Run call of every module started afterwards.
+	UseRunMiddleware(mw RunMiddleware)
+
+	// OnStarted registers a callback that runs once Start has launched every module.
+	OnStarted(fn func())
+
+	// OnModuleFailed registers a callback that runs whenever a module enters StateFailed.
+	OnModuleFailed(fn func(name string, err error))
+
+	// OnShutdownProgress registers a callback that ShutdownAll runs before stopping each module.
+	OnShutdownProgress(fn func(stopped, total int, current string))
+
+	// Subscribe returns a channel receiving an Event whenever a module changes lifecycle state.
+	Subscribe() <-chan Event
+
+	// OnEvent registers a hook called with every lifecycle Event.
+	OnEvent(fn func(Event))
+	// OnShutdownComplete registers a callback that ShutdownAll runs once every module is stopped and every Run has exited.
+	OnShutdownComplete(fn func())
+
 	// Shutdown gracefully shuts down modules in the order they are provided.
 	Shutdown(modules ...string)
+
+	// ShutdownE shuts down modules like Shutdown and also returns the stop errors together.
+	ShutdownE(modules ...string) error
+
+	// ShutdownAll gracefully shuts down every started module in the reverse order they were started,
+	// or in the order set with WithShutdownOrder.
+	ShutdownAll() error
+
+	// StartOrder returns the order Start would start the given modules in, without starting them.
+	StartOrder(modules ...string) ([]string, error)
+
+	// RestartCounts returns how many times each supervised module has been restarted.
+	RestartCounts() map[string]int
+
+	// Dependents returns the modules that depend on name, directly or transitively.
+	Dependents(name string) []string
+
+	// Rebind replaces the object bound under name and restarts the running modules depending on it.
+	Rebind(name string, obj any) error
+
+	// RestartAll stops every module, reloads the global configs, and initializes and starts the same modules again.
+	RestartAll() error
+
+	// BindGroup registers a module group named name over the given members.
+	BindGroup(name string, members ...string)
+
+	// Group returns the module group registered under name.
+	Group(name string) (ModuleGroup, bool)
+
+	// InitGroup initializes the members of the named group in dependency order.
+	InitGroup(name string) error
+
+	// StartGroup starts the members of the named group in dependency order, without blocking.
+	StartGroup(name string) error
+
+	// ShutdownGroup stops the members of the named group in reverse dependency order.
+	ShutdownGroup(name string) error
+
+	// StopModule stops a single running or initialized module while the rest keep running.
+	StopModule(name string) error
+
+	// SetEnabled enables or disables the named module. Disabled modules are skipped by Init, Start and shutdown.
+	SetEnabled(name string, enabled bool)
+
+	// ShutdownBefore shuts down modules in the order they are provided so that the whole teardown
+	// finishes before deadline, splitting the remaining time across modules.
+	ShutdownBefore(deadline time.Time, modules ...string) error
+
+	// Health runs the health check of every module implementing HealthCheckable.
+	Health(ctx context.Context) map[string]error
+
+	// CheckHealth runs the health checks like Health and reports the status and latency of each module.
+	CheckHealth(ctx context.Context) HealthReport
+
+	// HealthHandler returns an http.Handler serving the health report on /healthz and readiness on /readyz.
+	HealthHandler() http.Handler
+
+	// Liveness runs the liveness check of every module implementing LivenessChecker.
+	Liveness(ctx context.Context) map[string]error
+
+	// Readiness runs the readiness check of every module implementing ReadinessChecker.
+	Readiness(ctx context.Context) map[string]error
+
+	// SetLogOutput sends the container logs to w, such as io.Discard to silence them.
+	SetLogOutput(w io.Writer)
+
+	// Quiet mutes the container logs while quiet is true.
+	Quiet(quiet bool)
+
+	// Ready reports whether the container is started and every running module reports ready.
+	Ready() bool
+
+	// ReadinessChanged returns a channel receiving the new readiness whenever Ready flips.
+	ReadinessChanged() <-chan bool
+
+	// Uptime returns how long ago Start was called, or zero if the container hasn't been started.
+	Uptime() time.Duration
+
+	// Reset clears the lifecycle state of the container so it can be initialized and started again.
+	Reset()
+
+	// Report returns the lifecycle interfaces, dependencies and configs of every module.
+	Report() BootstrapReport
+
+	// Bindings returns the names of every binding except the container itself, sorted by name.
+	Bindings() []string
+
+	// DependencyGraph returns the bindings and the dependencies declared between them.
+	DependencyGraph() DependencyGraph
+
+	// Override replaces the object bound under name until the returned restore function is called.
+	Override(name string, obj any) (restore func())
+
+	// Snapshot copies the current bindings so they can be put back with Restore.
+	Snapshot() BindingSnapshot
+
+	// Restore puts back the bindings of a snapshot taken with Snapshot.
+	Restore(s BindingSnapshot)
+
+	// UninitializedInitables returns the initable modules that were never initialized.
+	UninitializedInitables() []string
+
+	// ResolveCounts returns how many times each binding has been resolved.
+	ResolveCounts() map[string]int
+
+	// Freeze seals the container so every later bind panics with ErrContainerFrozen.
+	Freeze()
+}
+
+// Configurable interface is used by modules that need to be set up in two phases.
+//
+// Configure is called on every module before Init is called on any of them, so modules can
+// register themselves or exchange references before completing their setup in Init.
+type Configurable interface {
+	Configure(Container) error
 }
 
 // Runnable interface is used for modules that needs a runnable process.
@@ -24,11 +200,35 @@ type Runnable interface {
 	Run() error
 }
 
+// RunnableCtx interface is used by runnable modules that honor cancellation. When a module
+// implements it, RunContext is called instead of Run, with the module's context, which is
+// cancelled when the module is stopped or shutdown begins.
+type RunnableCtx interface {
+	Initable
+
+	// RunContext starts the module and returns once ctx is done or the module fails.
+	RunContext(ctx context.Context) error
+}
+
 // Stoppable interface is used to gracefully stop running modules.
 type Stoppable interface {
 	Stop() error
 }
 
+// StoppableCtx interface is used by modules whose Stop honors a deadline. When a module
+// implements it, StopContext is called instead of Stop, with a context that expires once the
+// stop timeout and grace period have passed.
+type StoppableCtx interface {
+	StopContext(ctx context.Context) error
+}
+
+// ForceStoppable interface is used by modules that can be stopped forcibly when a graceful Stop hangs.
+//
+// ForceStop is called once the stop timeout and the grace period have both passed.
+type ForceStoppable interface {
+	ForceStop() error
+}
+
 type Validator interface {
 	Validator() error
 }
@@ -0,0 +1,139 @@
+package container
+
+import (
+	"sort"
+	"time"
+)
+
+// BindOption sets how a module bound with BindWithOptions takes part in the lifecycle.
+type BindOption func(*bindOptions)
+
+// bindOptions holds the lifecycle flags stored alongside a binding.
+type bindOptions struct {
+	skipHealthCheck    bool
+	skipAutoStart      bool
+	manualShutdownOnly bool
+	dependsOn          []string
+	stopTimeout        time.Duration
+	restart            RestartPolicy
+	maxRestarts        int
+	backoff            time.Duration
+	maxBackoff         time.Duration
+	critical           bool
+}
+
+// SkipHealthCheck leaves the module out of Health, Liveness and Readiness, even if it
+// implements their checks.
+func SkipHealthCheck() BindOption {
+	return func(o *bindOptions) {
+		o.skipHealthCheck = true
+	}
+}
+
+// SkipAutoStart leaves the module out of StartAll. It can still be started by name.
+func SkipAutoStart() BindOption {
+	return func(o *bindOptions) {
+		o.skipAutoStart = true
+	}
+}
+
+// ManualShutdownOnly leaves the module out of ShutdownAll, so it is only stopped by name
+// with Shutdown or StopModule.
+func ManualShutdownOnly() BindOption {
+	return func(o *bindOptions) {
+		o.manualShutdownOnly = true
+	}
+}
+
+// DependsOn declares hard dependencies of the module alongside the binding, for objects that
+// don't implement Dependent. They are added to the module's DependsOn, if it has one.
+func DependsOn(names ...string) BindOption {
+	return func(o *bindOptions) {
+		o.dependsOn = append(o.dependsOn, names...)
+	}
+}
+
+// StopTimeout sets the stop timeout of the module, overriding WithStopTimeout for it alone.
+func StopTimeout(d time.Duration) BindOption {
+	return func(o *bindOptions) {
+		o.stopTimeout = d
+	}
+}
+
+// Restart supervises the module's Run with policy, restarting it at most maxRestarts times,
+// or without limit if maxRestarts is negative. A module that exceeds its restart budget is
+// marked failed, and the container is shut down only if the module is Critical.
+func Restart(policy RestartPolicy, maxRestarts int) BindOption {
+	return func(o *bindOptions) {
+		o.restart = policy
+		o.maxRestarts = maxRestarts
+	}
+}
+
+// RestartBackoff sets the delay before the first restart of a supervised module, doubled
+// after each restart up to max. It defaults to 100ms, doubling up to 30s.
+func RestartBackoff(initial, max time.Duration) BindOption {
+	return func(o *bindOptions) {
+		o.backoff = initial
+		o.maxBackoff = max
+	}
+}
+
+// Critical makes the container shut down once the supervised module exceeds its restart budget.
+func Critical() BindOption {
+	return func(o *bindOptions) {
+		o.critical = true
+	}
+}
+
+// BindWithOptions binds obj under name like Bind and stores opts alongside the binding.
+//
+// The options stay with the name until it is bound again with BindWithOptions.
+func (c *container) BindWithOptions(name string, obj any, opts ...BindOption) {
+	var o bindOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.bind(name, obj)
+	c.bindOpts[c.key(name)] = o
+}
+
+// options returns the options stored for the named binding.
+func (c *container) options(name string) bindOptions {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.bindOpts[c.key(name)]
+}
+
+// StartAll starts every enabled runnable module, except those bound with SkipAutoStart,
+// in dependency order. Modules free to start at the same time start in name order.
+//
+// Like StartE it blocks until the container is stopped.
+func (c *container) StartAll() error {
+	c.lock.RLock()
+	names := c.store.Keys()
+	c.lock.RUnlock()
+	sort.Strings(names)
+
+	modules := make([]string, 0, len(names))
+	for _, name := range names {
+		if c.isSelf(name) || c.isDisabled(name) || c.options(name).skipAutoStart {
+			continue
+		}
+
+		m, err := c.module(name)
+		if err != nil {
+			return err
+		}
+		if isRunnable(m) {
+			modules = append(modules, name)
+		}
+	}
+
+	return c.StartE(modules...)
+}
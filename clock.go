@@ -0,0 +1,72 @@
+package container
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides time to the container's timeout logic, so tests can drive timeouts
+// deterministically with a fake clock.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the default Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// withTimeout is context.WithTimeout driven by the container's Clock, so contexts handed to
+// modules expire when a fake clock is advanced rather than on wall time.
+func (c *container) withTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := c.clock.(realClock); ok {
+		return context.WithTimeout(parent, d)
+	}
+
+	inner, cancel := context.WithCancel(parent)
+	ctx := &clockContext{Context: inner, deadline: c.clock.Now().Add(d)}
+	go func() {
+		select {
+		case <-c.clock.After(d):
+			ctx.expired.Store(true)
+			cancel()
+		case <-inner.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// withDeadline is context.WithDeadline driven by the container's Clock, see withTimeout.
+func (c *container) withDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	return c.withTimeout(parent, deadline.Sub(c.clock.Now()))
+}
+
+// clockContext is a context cancelled by withTimeout, reporting its Clock deadline.
+type clockContext struct {
+	context.Context
+	deadline time.Time
+	expired  atomic.Bool
+}
+
+func (ctx *clockContext) Deadline() (time.Time, bool) {
+	if deadline, ok := ctx.Context.Deadline(); ok && deadline.Before(ctx.deadline) {
+		return deadline, true
+	}
+	return ctx.deadline, true
+}
+
+func (ctx *clockContext) Err() error {
+	err := ctx.Context.Err()
+	if err != nil && ctx.expired.Load() {
+		return context.DeadlineExceeded
+	}
+	return err
+}
@@ -1,133 +1,774 @@
 package container
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	gocon "github.com/wgarunap/goconf"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+// SelfKey is the name the container binds itself under, so late-constructed objects can resolve it.
+const SelfKey = `container`
+
 type Initable interface {
 	Init(Container) error
 }
 type Container interface {
 	Init(modules ...string)
+
+	// InitE initializes modules like Init but returns the first failure instead of panicking.
+	InitE(modules ...string) error
+
+	// InitWithContext initializes modules like InitE, rolling back the initialized ones if ctx is cancelled first.
+	InitWithContext(ctx context.Context, modules ...string) error
+
 	Bind(typ string, obj any)
+
+	// BindWithOptions binds obj under name like Bind and stores opts alongside the binding.
+	BindWithOptions(name string, obj any, opts ...BindOption)
+
+	// StopChannel returns a channel modules can send on to request a graceful shutdown.
+	StopChannel() chan<- any
+
+	// NotifyStop requests a graceful shutdown without blocking.
+	NotifyStop()
+
+	// Scope creates a child container whose context is cancelled with the context of this one.
+	Scope() Scope
+
+	// BindWithFinalizer binds obj under name with a best-effort cleanup that runs even when shutdown isn't graceful.
+	BindWithFinalizer(name string, obj any, finalize func())
+
+	// BindNonNil binds obj under name like Bind, but panics if obj is nil or a nil pointer.
+	BindNonNil(name string, obj any)
+
+	// BindAll binds every entry of bindings in one call.
+	BindAll(bindings map[string]any)
+
+	// BindList binds the given bindings in one call, in the order they are provided.
+	BindList(bindings ...Binding)
+
 	Resolve(name string) any
+
+	// TryResolve resolves the named object like Resolve but returns an error instead of panicking.
+	TryResolve(name string) (any, error)
+
+	// ResolveMany resolves all the named objects, returning them in the order of names.
+	ResolveMany(names ...string) ([]any, error)
+
 	GetGlobalConfig(typ string) any
+
+	// TryGlobalConfig returns the global config registered under typ like GetGlobalConfig but returns an error instead of panicking.
+	TryGlobalConfig(typ string) (any, error)
+
+	// LookupGlobalConfig returns the global config registered under typ and whether there is one, without panicking.
+	LookupGlobalConfig(typ string) (any, bool)
+
+	// BindFactory binds a factory that constructs the object on resolve instead of up front.
+	BindFactory(name string, fn Factory, scope FactoryScope)
+
+	// BindEnv binds name to the value of the environment variable envVar, read the first time name is resolved.
+	BindEnv(name, envVar string)
+
+	// RunFunc binds a module under name that runs fn until the module is stopped.
+	RunFunc(name string, fn func(ctx context.Context) error)
+	// RunFuncWithRestarts binds a module like RunFunc that restarts fn up to maxRestarts times when it fails.
+	RunFuncWithRestarts(name string, maxRestarts int, fn func(ctx context.Context) error)
+
+	// ResolveWithContext resolves the named object, giving up on a factory once ctx is done.
+	ResolveWithContext(ctx context.Context, name string) (any, error)
+
+	// BindInterface binds impl under the interface type ifacePtr points to, given as a typed nil such as (*Cache)(nil).
+	BindInterface(ifacePtr any, impl any)
+
+	// ResolveInterface resolves the object bound under the interface type ifacePtr points to.
+	ResolveInterface(ifacePtr any) any
+
+	// Inject populates the fields of target tagged with `inject:"name"` with the objects bound under those names.
+	Inject(target any) error
+
+	// ResolveAndBind resolves resolveName and binds the result under bindName, returning it.
+	ResolveAndBind(resolveName, bindName string) (any, error)
+
+	// Memoize replaces the factory binding under name with the object it resolves to.
+	Memoize(name string) error
+
+	// BindToGroup appends obj to the named group.
+	BindToGroup(group string, obj any)
+
+	// ResolveGroup returns the objects of the named group in the order they were bound.
+	ResolveGroup(group string) []any
+
+	// WithValue stores val under key on the container.
+	WithValue(key, val any)
+
+	// Value returns the value stored under key, or nil if there is none.
+	Value(key any) any
+
+	// State returns the lifecycle state of the named module.
+	State(name string) ModuleState
+
+	// IsRunning reports whether the named module is running.
+	IsRunning(name string) bool
+
+	// IsShuttingDown reports whether shutdown has begun. It is safe to call from any goroutine.
+	IsShuttingDown() bool
+
+	// ShutdownReason returns what initiated the shutdown.
+	ShutdownReason() ShutdownReason
+
+	// Context returns the context of the container, which is cancelled once shutdown begins.
+	Context() context.Context
+
+	// ModuleContext returns the context of the named module, which is also cancelled when that module alone is stopped.
+	ModuleContext(name string) context.Context
+
+	// Logger returns the logger used by the container, so modules can log to the same destination.
+	Logger() *log.Logger
+
+	// StructuredLogger returns a structured logger writing to the same destination as Logger.
+	StructuredLogger() StructuredLogger
 }
 
 type container struct {
-	bindings      map[string]any
+	store         Store
+	groups        map[string][]any
+	moduleGroups  map[string]ModuleGroup
+	values        map[any]any
 	moduleConfigs map[string]any
-	stopSigs      []<-chan any // channel for shutdown signals
+	stopSigs      []<-chan any   // channel for shutdown signals
+	allStopSigs   [][]<-chan any // sets of channels that must all fire before shutdown
 	stopped       chan struct{}
-	lock          sync.Mutex
+	stopOnce      sync.Once
+	started       []string // modules in the order they were started
+	initialized   []string // modules in the order they were initialized
+	lock          sync.RWMutex
+	snapshot      atomic.Pointer[map[string]any] // immutable copy of the bindings once frozen
 	logger        *log.Logger
+	mutedOutput   io.Writer // output to restore once Quiet(false) is called
+	clock         Clock
+	resolveCounts sync.Map // binding name to *atomic.Int64
+	runErr        error    // first Run failure when panics are recovered
+	runErrs       []error  // every Run failure, for RunToCompletion
+	running       sync.WaitGroup
+	completing    atomic.Bool // RunToCompletion is waiting for the modules
+	startedAt     time.Time
+	bootedAt      time.Time // first Init or Start, for the startup deadline
+	shuttingDown  atomic.Bool
+	reason        ShutdownReason
+	ctx           context.Context
+	cancel        context.CancelFunc
+	moduleCtxs    map[string]moduleContext
+	frozen        bool
+	onStarted     []func()
+	onFailed      []func(name string, err error)
+	onProgress    []func(stopped, total int, current string)
+	onComplete    []func()
+	completed     bool // OnShutdownComplete callbacks ran for this shutdown
+	runMiddleware []RunMiddleware
+	states        map[string]ModuleState
+	disabled      map[string]bool
+	bindOpts      map[string]bindOptions
+
+	autoInject        bool
+	recoverPanics     bool
+	errorHandler      func(error)
+	restarts          map[string]int // restarts of supervised modules, by name
+	reportUnresolved  bool
+	strict            bool
+	strictResolve     bool
+	earlyExitWindow   time.Duration
+	earlyExitFails    bool
+	stopOrder         []string
+	stopTimeout       time.Duration
+	stopGracePeriod   time.Duration
+	healthTimeout     time.Duration
+	events            eventBus
+	healthParallelism int
+	startupDeadline   time.Duration
+	bindTimeout       time.Duration
+	constructing      resolveChain // constructions in progress under the bind timeout
+	signalShutdown    bool
+	signalSource      SignalSource
+	signals           []os.Signal
+	stopRequests      chan any // stop requests sent with StopChannel or NotifyStop
+	keyNormalizer     func(string) string
+	observeDeps       bool
+	race              *race // modules racing in StartRace
+	finalizers        []*finalizer
+	initializing      string                     // module whose Init is running, for observed dependencies
+	observed          map[string]map[string]bool // module -> modules it resolved during Init
+	tracer            trace.Tracer
+	bootSpan          trace.Span // container.bootstrap span parenting the init and start spans
+	bootEnded         bool       // the bootstrap span has ended, so later spans aren't parented on it
+	parent            *container // container a scope was created from
+	ready             atomic.Bool
+	readyChanged      chan bool
+	readinessInterval time.Duration
+
+	configWatchInterval time.Duration
+	configWatchFiles    []string
 }
 
-func NewContainer() AppContainer {
-	return &container{
-		bindings:      map[string]any{},
+func NewContainer(opts ...Option) AppContainer {
+	c := &container{
+		store:         mapStore{},
+		groups:        map[string][]any{},
+		moduleGroups:  map[string]ModuleGroup{},
+		values:        map[any]any{},
 		moduleConfigs: map[string]any{},
-		lock:          sync.Mutex{},
+		states:        map[string]ModuleState{},
+		disabled:      map[string]bool{},
+		bindOpts:      map[string]bindOptions{},
 		stopSigs:      []<-chan any{},
 		stopped:       make(chan struct{}, 1),
+		readyChanged:  make(chan bool, 1),
+		stopRequests:  make(chan any, 1),
+		restarts:      make(map[string]int),
 		logger:        log.New(os.Stdout, `di`, log.LstdFlags),
+		clock:         realClock{},
+		tracer:        noop.NewTracerProvider().Tracer(``),
+
+		earlyExitWindow:   100 * time.Millisecond,
+		healthParallelism: runtime.NumCPU(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.resetContexts()
+	c.bindSelf()
+
+	return c
+}
+
+// bindSelf binds the container under SelfKey and under the Container type key.
+//
+// These bindings are skipped by anything that walks the bindings, so the container never
+// inspects itself.
+func (c *container) bindSelf() {
+	c.checkKey(SelfKey)
+	c.store.Set(c.key(SelfKey), c)
+	c.store.Set(c.key(typeKey[Container]()), c)
+}
+
+// isSelf reports whether name is one of the bindings that refer to the container itself.
+func (c *container) isSelf(name string) bool {
+	name = c.key(name)
+	return name == c.key(SelfKey) || name == c.key(typeKey[Container]())
+}
+
+// key returns the binding key for name, normalized with the function set by WithKeyNormalizer.
+func (c *container) key(name string) string {
+	if c.keyNormalizer == nil {
+		return name
+	}
+	return c.keyNormalizer(name)
+}
+
+// checkKey panics if normalizing the key for name again changes it. Stored keys are passed
+// back through key by the lifecycle, so a normalizer that isn't idempotent would make them
+// refer to bindings that don't exist.
+func (c *container) checkKey(name string) {
+	if c.keyNormalizer == nil {
+		return
+	}
+	if key := c.key(name); c.key(key) != key {
+		panic(fmt.Errorf(`container: key normalizer is not idempotent, [%s] normalizes to [%s] and then [%s]`, name, key, c.key(key)))
 	}
 }
 
+// keys normalizes every name with key.
+func (c *container) keys(names []string) []string {
+	if c.keyNormalizer == nil {
+		return names
+	}
+
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = c.key(name)
+	}
+	return keys
+}
+
 func (c *container) Bind(typ string, obj any) {
-	c.bindings[typ] = obj
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.bind(typ, obj)
 }
 
-func (c *container) Init(modules ...string) {
-	for _, name := range modules {
-		if in, ok := c.bindings[name].(Initable); ok {
-			err := in.Init(c)
-			if err != nil {
-				panic(err)
-			}
-		}
+// BindNonNil binds obj under name like Bind, but panics if obj is nil or a nil pointer,
+// catching a failed constructor at the line that bound its result.
+func (c *container) BindNonNil(name string, obj any) {
+	if isNil(obj) {
+		panic(fmt.Sprintf(`container: binding [%s] is nil, binding failed`, name))
+	}
+	c.Bind(name, obj)
+}
+
+// BindAll binds every entry of bindings in one call.
+func (c *container) BindAll(bindings map[string]any) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for name, obj := range bindings {
+		c.bind(name, obj)
 	}
 }
 
+// BindList binds the given bindings in one call, in the order they are provided.
+func (c *container) BindList(bindings ...Binding) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, b := range bindings {
+		c.bind(b.Name, b.Obj)
+	}
+}
+
+// bind binds obj under name. The caller must hold the lock.
+func (c *container) bind(name string, obj any) {
+	c.checkKey(name)
+	name = c.key(name)
+	c.checkFrozen(name)
+
+	c.store.Set(name, obj)
+	if !c.disabled[name] {
+		c.states[name] = StateRegistered
+	}
+}
+
+// Freeze seals the container against further binds, enforcing that wiring is complete
+// before the modules run. Every later Bind, BindFactory or BindToGroup panics with
+// ErrContainerFrozen. Resolving keeps working, and Reset does not unfreeze.
+//
+// Once frozen, resolves read an immutable snapshot of the bindings and take no lock at all.
+func (c *container) Freeze() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.frozen {
+		return
+	}
+	c.frozen = true
+
+	snapshot := make(map[string]any)
+	for _, name := range c.store.Keys() {
+		snapshot[name], _ = c.store.Get(name)
+	}
+	c.snapshot.Store(&snapshot)
+}
+
+// checkFrozen panics with ErrContainerFrozen if the container is frozen. The caller must hold the lock.
+func (c *container) checkFrozen(name string) {
+	if c.frozen {
+		panic(fmt.Errorf(`%w, binding [%s] refused`, ErrContainerFrozen, name))
+	}
+}
+
+// isBound reports whether anything is bound under name.
+func (c *container) isBound(name string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	_, ok := c.store.Get(c.key(name))
+	return ok
+}
+
+// binding returns the object bound under name without counting it as a resolve.
+//
+// Factory bindings yield their object only once it has been constructed.
+func (c *container) binding(name string) any {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	obj, _ := c.store.Get(c.key(name))
+	return constructed(obj)
+}
+
+// module returns the object bound under name for use by the lifecycle, constructing
+// singleton factory bindings. Transient factory bindings yield nil.
+func (c *container) module(name string) (any, error) {
+	c.lock.RLock()
+	obj, _ := c.store.Get(c.key(name))
+	c.lock.RUnlock()
+
+	f, ok := obj.(*factory)
+	if !ok {
+		return obj, nil
+	}
+	if f.scope == Transient {
+		return nil, nil
+	}
+
+	obj, err := c.construct(name, f)
+	if err != nil {
+		return nil, fmt.Errorf(`container: constructing [%s] failed: %w`, name, err)
+	}
+	return obj, nil
+}
+
 func (c *container) Resolve(name string) any {
-	if con, ok := c.bindings[name]; ok {
-		return con
+	obj, err := c.TryResolve(name)
+	if err != nil {
+		c.fail(err)
+		return nil
 	}
-	panic(fmt.Sprintf(`%s no module`, name))
+	return obj
 }
 
-func (c *container) GetGlobalConfig(typ string) any {
-	if config, ok := c.moduleConfigs[typ]; ok {
-		return config
+// TryResolve resolves the named object like Resolve but returns an error instead of panicking.
+func (c *container) TryResolve(name string) (any, error) {
+	con, err := c.lookup(name)
+	if errors.Is(err, ErrModuleNotFound) && c.parent != nil {
+		return c.parent.TryResolve(name)
 	}
-	panic(fmt.Sprintf(`%s no module`, typ))
+	if err != nil {
+		return nil, err
+	}
+
+	if f, ok := con.(*factory); ok {
+		obj, err := c.construct(name, f)
+		if err != nil {
+			return nil, fmt.Errorf(`container: constructing [%s] failed: %w`, name, err)
+		}
+		return obj, nil
+	}
+	return con, nil
 }
 
-func (c *container) Start(modules ...string) {
-	for _, sig := range c.stopSigs {
-		go func(ch <-chan any) {
-			<-ch
-			// initiate graceful shutdown
-			c.stopped <- struct{}{}
-		}(sig)
+// ResolveMany resolves all the named objects, returning them in the order of names.
+//
+// Every name is tried, so the error lists all missing names at once instead of stopping
+// at the first. It wraps ErrModuleNotFound, joined with any construction failures.
+func (c *container) ResolveMany(names ...string) ([]any, error) {
+	objs := make([]any, len(names))
+	missing := make([]string, 0)
+	errs := make([]error, 0)
+	for i, name := range names {
+		obj, err := c.TryResolve(name)
+		switch {
+		case errors.Is(err, ErrModuleNotFound):
+			missing = append(missing, name)
+		case err != nil:
+			errs = append(errs, err)
+		default:
+			objs[i] = obj
+		}
 	}
 
-	for _, module := range modules {
-		c.logger.Printf(`module %s starting...`, module)
+	if len(missing) > 0 {
+		errs = append([]error{fmt.Errorf(`%w [%s]`, ErrModuleNotFound, strings.Join(missing, `, `))}, errs...)
+	}
+	if len(errs) > 0 {
+		return objs, errors.Join(errs...)
+	}
+	return objs, nil
+}
 
-		m := c.bindings[module]
+// lookup returns the binding stored under name and counts it as a resolve.
+//
+// Concurrent lookups only share a read lock, and none is taken once the container is frozen.
+func (c *container) lookup(name string) (any, error) {
+	if c.strictResolve && c.IsShuttingDown() {
+		return nil, fmt.Errorf(`%w, refused to resolve [%s]`, ErrShuttingDown, name)
+	}
+	name = c.key(name)
 
-		runnable, ok := m.(Runnable)
-		if !ok {
-			panic(fmt.Sprintf(`container: module [%s] is not runnable, starting failed`, module))
+	var (
+		con any
+		ok  bool
+	)
+	if snapshot := c.snapshot.Load(); snapshot != nil {
+		con, ok = (*snapshot)[name]
+	} else {
+		c.lock.RLock()
+		con, ok = c.store.Get(name)
+		c.lock.RUnlock()
+	}
+	if !ok {
+		return nil, &notFoundError{c: c, name: name}
+	}
+	c.countResolve(name)
+	if c.observeDeps {
+		c.observeResolve(name)
+	}
+
+	return con, nil
+}
+
+// knownBindings returns the sorted names bound in the container, leaving out the container itself.
+func (c *container) knownBindings() []string {
+	c.lock.RLock()
+	keys := c.store.Keys()
+	c.lock.RUnlock()
+
+	names := make([]string, 0, len(keys))
+	for _, name := range keys {
+		if !c.isSelf(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// countResolve counts a resolve of name without taking the container lock.
+func (c *container) countResolve(name string) {
+	n, ok := c.resolveCounts.Load(name)
+	if !ok {
+		n, _ = c.resolveCounts.LoadOrStore(name, new(atomic.Int64))
+	}
+	n.(*atomic.Int64).Add(1)
+}
+
+// ResolveCounts returns how many times each binding has been resolved.
+//
+// Bindings that were never resolved are included with a count of zero.
+func (c *container) ResolveCounts() map[string]int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	keys := c.store.Keys()
+	counts := make(map[string]int, len(keys))
+	for _, name := range keys {
+		counts[name] = 0
+		if n, ok := c.resolveCounts.Load(name); ok {
+			counts[name] = int(n.(*atomic.Int64).Load())
 		}
-		go func(r Runnable) {
-			if err := r.Run(); err != nil {
-				panic(err)
-			}
-		}(runnable)
+	}
+	return counts
+}
 
-		c.logger.Printf(`module %s started`, module)
+// logUnresolved logs the bindings that were never resolved.
+func (c *container) logUnresolved() {
+	counts := c.ResolveCounts()
+	names := make([]string, 0)
+	for name, count := range counts {
+		if count == 0 && !c.isSelf(name) {
+			names = append(names, name)
+		}
 	}
+	sort.Strings(names)
 
-	<-c.stopped
+	for _, name := range names {
+		c.logger.Printf(`binding %s never resolved`, name)
+	}
+}
+
+func (c *container) GetGlobalConfig(typ string) any {
+	config, err := c.TryGlobalConfig(typ)
+	if err != nil {
+		c.fail(err)
+		return nil
+	}
+	return config
+}
+
+// TryGlobalConfig returns the global config registered under typ like GetGlobalConfig, but
+// returns an error wrapping ErrConfigNotFound, listing the configs that are set, instead of panicking.
+func (c *container) TryGlobalConfig(typ string) (any, error) {
+	if config, ok := c.moduleConfig(typ); ok {
+		return config, nil
+	}
+
+	c.lock.Lock()
+	keys := make([]string, 0, len(c.moduleConfigs))
+	for key := range c.moduleConfigs {
+		keys = append(keys, key)
+	}
+	c.lock.Unlock()
+	sort.Strings(keys)
+
+	return nil, fmt.Errorf(`%w [%s], known configs [%s]`, ErrConfigNotFound, typ, strings.Join(keys, `, `))
+}
+
+// LookupGlobalConfig returns the global config registered under typ and whether there is
+// one. Unlike GetGlobalConfig it never panics.
+func (c *container) LookupGlobalConfig(typ string) (any, bool) {
+	return c.moduleConfig(typ)
+}
+
+// moduleConfig returns the global config registered under key, falling back to the parent of a scope.
+func (c *container) moduleConfig(key string) (any, bool) {
+	c.lock.Lock()
+	config, ok := c.moduleConfigs[key]
+	c.lock.Unlock()
+
+	if !ok && c.parent != nil {
+		return c.parent.moduleConfig(key)
+	}
+	return config, ok
+}
+
+func (c *container) IsShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+func (c *container) Logger() *log.Logger {
+	return c.logger
+}
+
+// SetLogOutput sends the container logs to w from now on, such as io.Discard to silence
+// them. While the container is quiet, w is where logging resumes once it is unmuted.
+func (c *container) SetLogOutput(w io.Writer) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.mutedOutput != nil {
+		c.mutedOutput = w
+		return
+	}
+	c.logger.SetOutput(w)
+}
+
+// Quiet mutes the container logs while quiet is true and restores the previous output once
+// it is false again. It is safe to call concurrently with logging.
+func (c *container) Quiet(quiet bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	switch {
+	case quiet && c.mutedOutput == nil:
+		c.mutedOutput = c.logger.Writer()
+		c.logger.SetOutput(io.Discard)
+	case !quiet && c.mutedOutput != nil:
+		c.logger.SetOutput(c.mutedOutput)
+		c.mutedOutput = nil
+	}
 }
 
 // SetModuleGlobalConfig adds static configurations of modules in to the container.
+//
+// Every entry is checked before anything is stored or loaded: it needs a key and a non-nil
+// value implementing goconf.Configer.
 func (c *container) SetModuleGlobalConfig(configs ...ModuleConfig) error {
 	cfgs := make([]gocon.Configer, 0)
+	for i, value := range configs {
+		if value.Key == `` {
+			return fmt.Errorf(`config at index %d has no key`, i)
+		}
+		if isNil(value.Value) {
+			return fmt.Errorf(`config %q is nil`, value.Key)
+		}
+		cfg, ok := value.Value.(gocon.Configer)
+		if !ok {
+			return fmt.Errorf(`config %q does not implement goconf.Configer`, value.Key)
+		}
+		cfgs = append(cfgs, cfg)
+	}
+
+	c.lock.Lock()
 	for _, value := range configs {
-		cfgs = append(cfgs, value.Value.(gocon.Configer))
 		c.moduleConfigs[value.Key] = value.Value
 	}
+	c.lock.Unlock()
+
 	return gocon.Load(cfgs...)
 }
 
-// Shutdown gracefully shuts down modules in the order they are provided.
-func (c *container) Shutdown(modules ...string) {
-	// un register channels
-
-	// stop modules
-	for _, module := range modules {
-		c.logger.Printf(`module %s stopping...`, module)
+// bindingWalker is implemented by containers that can list their bindings for introspection.
+type bindingWalker interface {
+	walkBindings() []Binding
+}
 
-		m := c.bindings[module]
+// walkBindings returns every binding except the container itself, sorted by name.
+//
+// Factory bindings are only included once they are constructed.
+func (c *container) walkBindings() []Binding {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-		stoppable, ok := m.(Stoppable)
-		if !ok {
-			panic(fmt.Sprintf(`container: module [%s] is not stoppable, stopping failed`, module))
+	keys := c.store.Keys()
+	bindings := make([]Binding, 0, len(keys))
+	for _, name := range keys {
+		if c.isSelf(name) {
+			continue
 		}
-		if err := stoppable.Stop(); err != nil {
-			c.logger.Println(err)
+		obj, _ := c.store.Get(name)
+		if obj = constructed(obj); obj != nil {
+			bindings = append(bindings, Binding{Name: name, Obj: obj})
 		}
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Name < bindings[j].Name
+	})
+
+	return bindings
+}
+
+// isNil reports whether obj is nil or a typed nil such as a nil pointer.
+func isNil(obj any) bool {
+	if obj == nil {
+		return true
+	}
 
-		c.logger.Printf(`module %s stopped`, module)
+	v := reflect.ValueOf(obj)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	default:
+		return false
 	}
+}
+
+// Reset clears the lifecycle state of the container so it can be initialized and started again.
+//
+// Bindings, configs and registered callbacks are kept. Every module goes back to
+// StateRegistered, or StateDisabled if it is disabled. Event subscriber channels are closed
+// and the subscribers forgotten. Reset must not be called while
+// modules are running.
+func (c *container) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	c.stopped <- struct{}{}
+	c.runErr = nil
+	c.runErrs = nil
+	c.startedAt = time.Time{}
+	c.bootedAt = time.Time{}
+	c.stopped = make(chan struct{}, 1)
+	c.stopOnce = sync.Once{}
+	c.observed = nil
+	c.restarts = make(map[string]int)
+	c.resetModules()
+	c.closeEvents()
+}
+
+// resetModules forgets which modules were initialized and started, ends the shutdown and
+// puts every module back to StateRegistered or StateDisabled. The caller must hold the lock.
+func (c *container) resetModules() {
+	c.started = nil
+	c.initialized = nil
+	c.completed = false
+	c.shuttingDown.Store(false)
+	c.reason = ShutdownReason{}
+	if c.bootSpan != nil && !c.bootEnded {
+		c.bootSpan.End()
+	}
+	c.bootSpan = nil
+	c.bootEnded = false
+	c.resetContexts()
+
+	for name := range c.states {
+		if c.disabled[name] {
+			c.states[name] = StateDisabled
+			continue
+		}
+		c.states[name] = StateRegistered
+	}
 }
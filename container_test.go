@@ -0,0 +1,30 @@
+package container
+
+import "testing"
+
+func BenchmarkResolveParallel(b *testing.B) {
+	for _, frozen := range []bool{false, true} {
+		name := `locked`
+		if frozen {
+			name = `frozen`
+		}
+
+		b.Run(name, func(b *testing.B) {
+			c := NewContainer()
+			c.Bind(`db`, struct{}{})
+			if frozen {
+				c.Freeze()
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := c.TryResolve(`db`); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
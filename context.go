@@ -0,0 +1,75 @@
+package container
+
+import "context"
+
+// moduleContext is the context handed to a single module and the function cancelling it.
+type moduleContext struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Context returns the context of the container, which is cancelled once shutdown begins.
+func (c *container) Context() context.Context {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.ctx
+}
+
+// ModuleContext returns the context of the named module.
+//
+// It is derived from the container context and is also cancelled when that module alone
+// is stopped, so goroutines a module started from it unwind without affecting the rest.
+func (c *container) ModuleContext(name string) context.Context {
+	name = c.key(name)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	mc, ok := c.moduleCtxs[name]
+	if !ok {
+		ctx, cancel := context.WithCancel(c.ctx)
+		mc = moduleContext{ctx: ctx, cancel: cancel}
+		c.moduleCtxs[name] = mc
+	}
+	return mc.ctx
+}
+
+// cancelModule cancels the context of the named module, if it has one.
+func (c *container) cancelModule(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if mc, ok := c.moduleCtxs[name]; ok {
+		mc.cancel()
+	}
+}
+
+// renewModuleContext drops the context of the named module if it was cancelled by a stop,
+// so the module gets a fresh one when it is initialized again.
+func (c *container) renewModuleContext(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if mc, ok := c.moduleCtxs[name]; ok && mc.ctx.Err() != nil && c.ctx.Err() == nil {
+		delete(c.moduleCtxs, name)
+	}
+}
+
+// resetContexts replaces the container context and drops the module contexts. The context
+// of a scope is derived from the context of its parent. The caller must hold the lock.
+func (c *container) resetContexts() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	for _, mc := range c.moduleCtxs {
+		mc.cancel()
+	}
+
+	base := context.Background()
+	if c.parent != nil {
+		base = c.parent.Context()
+	}
+	c.ctx, c.cancel = context.WithCancel(base)
+	c.moduleCtxs = map[string]moduleContext{}
+}
@@ -0,0 +1,116 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+var (
+	// ErrModuleNotFound is returned when resolving a name nothing is bound to.
+	ErrModuleNotFound = errors.New(`container: module not found`)
+
+	// ErrConfigNotFound is returned when looking up a global config that is not set.
+	ErrConfigNotFound = errors.New(`container: config not found`)
+
+	// ErrShuttingDown is returned by resolves once shutdown has begun, when WithStrictResolve is set.
+	ErrShuttingDown = errors.New(`container: shutting down`)
+
+	// ErrStopTimeout is returned when a module's Stop does not return in time.
+	ErrStopTimeout = errors.New(`container: stop timed out`)
+
+	// ErrBindTimeout is returned when a factory binding isn't constructed within the bind timeout.
+	ErrBindTimeout = errors.New(`container: construction timed out`)
+
+	// ErrStartupTimeout is returned by StartE when startup doesn't complete within the startup deadline.
+	ErrStartupTimeout = errors.New(`container: startup deadline exceeded`)
+
+	// ErrContainerFrozen is the panic raised by binds once the container has been frozen.
+	ErrContainerFrozen = errors.New(`container: frozen`)
+)
+
+// Phase names the lifecycle step a module was in.
+type Phase string
+
+const (
+	PhaseConfigure    Phase = `configure`
+	PhaseInit         Phase = `init`
+	PhaseRun          Phase = `run`
+	PhaseStop         Phase = `stop`
+	PhaseForceStop    Phase = `force stop`
+	PhaseHealthCheck  Phase = `health check`
+	PhaseConfigReload Phase = `config reload`
+	PhaseValidate     Phase = `startup validation`
+)
+
+// LifecyclePanic describes a panic raised by a module, with the lifecycle phase it was
+// raised in and the stack where it happened.
+//
+// When panics are recovered it is returned as an error. Otherwise the container panics
+// again with it, so the crash still says which module and phase panicked.
+type LifecyclePanic struct {
+	Module string
+	Phase  Phase
+	Value  any
+	Stack  []byte
+}
+
+func (e *LifecyclePanic) Error() string {
+	return fmt.Sprintf("container: module [%s] panicked during %s: %v\n%s", e.Module, e.Phase, e.Value, e.Stack)
+}
+
+// notFoundError is returned when nothing is bound under name. The known bindings listed in
+// the message are only collected once Error is called, so a miss that is handled, such as
+// by a scope falling back to its parent, doesn't pay for building the list.
+type notFoundError struct {
+	c    *container
+	name string
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf(`%s [%s], known bindings [%s]`, ErrModuleNotFound, e.name, strings.Join(e.c.knownBindings(), `, `))
+}
+
+func (e *notFoundError) Unwrap() error {
+	return ErrModuleNotFound
+}
+
+// fail surfaces an error the panicking API would otherwise panic with, handing it to the
+// handler set with WithErrorHandler when there is one.
+func (c *container) fail(err error) {
+	if c.errorHandler == nil {
+		panic(err)
+	}
+	c.errorHandler(err)
+}
+
+// call invokes fn on behalf of module in the given phase, turning a panic into a
+// *LifecyclePanic. The panic is returned as an error when panics are recovered, and raised
+// again otherwise.
+func (c *container) call(module string, phase Phase, fn func() error) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		p := asLifecyclePanic(module, phase, r)
+		if !c.recoverPanics {
+			c.runFinalizers()
+			panic(p)
+		}
+		err = p
+	}()
+
+	return fn()
+}
+
+// asLifecyclePanic describes the recovered value r as a *LifecyclePanic, keeping one raised
+// by a nested call as it is. It must be called from the deferred function that recovered r.
+func asLifecyclePanic(module string, phase Phase, r any) *LifecyclePanic {
+	if p, ok := r.(*LifecyclePanic); ok {
+		return p
+	}
+	return &LifecyclePanic{Module: module, Phase: phase, Value: r, Stack: debug.Stack()}
+}
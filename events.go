@@ -0,0 +1,94 @@
+package container
+
+import (
+	"sync"
+	"time"
+)
+
+// eventBuffer is how many events a subscriber can fall behind before further events are dropped for it.
+const eventBuffer = 64
+
+// Event describes a module entering a lifecycle state.
+type Event struct {
+	Module   string
+	State    ModuleState
+	Err      error         // set when the module failed, or for a restart the failure that caused it
+	Duration time.Duration // how long the Init or Stop that led to the state took
+	Restarts int           // set when a supervised module is restarted, counting this restart
+	Time     time.Time
+}
+
+// eventBus fans events out to subscriber channels without ever blocking the lifecycle, and
+// to the hooks registered with OnEvent.
+type eventBus struct {
+	lock  sync.Mutex
+	subs  []chan Event
+	hooks []func(Event)
+}
+
+// Subscribe returns a channel receiving a lifecycle Event whenever a module is initialized,
+// starts running, is restarted by its supervisor, is cancelled, stops or fails.
+//
+// Events are dropped for a subscriber that falls too far behind, so a slow subscriber never
+// holds up the container. The channel is closed by ShutdownAll and by Reset, which ends a
+// subscriber ranging over it.
+func (c *container) Subscribe() <-chan Event {
+	c.events.lock.Lock()
+	defer c.events.lock.Unlock()
+
+	ch := make(chan Event, eventBuffer)
+	c.events.subs = append(c.events.subs, ch)
+	return ch
+}
+
+// OnEvent registers a hook called with every lifecycle Event, such as to drive metrics.
+//
+// Unlike a Subscribe channel, no event is ever dropped for a hook. Hooks are called in
+// registration order on the goroutine driving the lifecycle, so they must return quickly.
+func (c *container) OnEvent(fn func(Event)) {
+	c.events.lock.Lock()
+	defer c.events.lock.Unlock()
+
+	c.events.hooks = append(c.events.hooks, fn)
+}
+
+// emit sends an event for the module entering state to every subscriber and hook.
+func (c *container) emit(module string, state ModuleState, err error) {
+	c.publish(Event{Module: module, State: state, Err: err})
+}
+
+// publish timestamps e and sends it to every subscriber and hook. It must not be called
+// with the container lock held, as hooks may call back into the container.
+func (c *container) publish(e Event) {
+	c.events.lock.Lock()
+	if len(c.events.subs) == 0 && len(c.events.hooks) == 0 {
+		c.events.lock.Unlock()
+		return
+	}
+
+	e.Time = c.clock.Now()
+	for _, ch := range c.events.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	hooks := append([]func(Event){}, c.events.hooks...)
+	c.events.lock.Unlock()
+
+	for _, fn := range hooks {
+		fn(e)
+	}
+}
+
+// closeEvents closes every subscriber channel and forgets the subscribers, so later
+// subscribers get a fresh channel.
+func (c *container) closeEvents() {
+	c.events.lock.Lock()
+	defer c.events.lock.Unlock()
+
+	for _, ch := range c.events.subs {
+		close(ch)
+	}
+	c.events.subs = nil
+}
@@ -0,0 +1,44 @@
+package container
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResetClosesSubscribers(t *testing.T) {
+	c := NewContainer()
+	events := c.Subscribe()
+
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for range events {
+		}
+	}()
+
+	c.Reset()
+
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal(`subscriber still running after Reset`)
+	}
+}
+
+func TestShutdownAllClosesSubscribers(t *testing.T) {
+	c := NewContainer()
+	events := c.Subscribe()
+
+	if err := c.ShutdownAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatal(`expected the events channel to be closed`)
+		}
+	case <-time.After(time.Second):
+		t.Fatal(`events channel still open after ShutdownAll`)
+	}
+}
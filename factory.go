@@ -0,0 +1,370 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Factory constructs the object of a lazy binding.
+type Factory func(Container) (any, error)
+
+// FactoryScope controls how often a factory binding constructs its object.
+type FactoryScope int
+
+const (
+	// Singleton constructs the object once, on first resolve, and reuses it afterwards.
+	Singleton FactoryScope = iota
+	// Transient constructs a new object on every resolve.
+	//
+	// Transient bindings are never initialized, started or stopped by the container.
+	Transient
+)
+
+// factory is the binding stored for objects bound with BindFactory.
+type factory struct {
+	fn     Factory
+	scope  FactoryScope
+	lock   sync.Mutex
+	built  bool
+	obj    any
+	flight *flight // singleton construction in progress
+}
+
+// flight is a singleton construction in progress, shared by every resolve waiting for it.
+// The result fields are set before done is closed.
+type flight struct {
+	done     chan struct{}
+	obj      any
+	err      error
+	panicked any
+}
+
+// get returns the object of the factory, constructing it if needed.
+//
+// A failed construction is not cached, so a later resolve tries again.
+func (f *factory) get(c Container) (any, error) {
+	if f.scope == Transient {
+		return f.fn(c)
+	}
+
+	fl, owner := f.start()
+	if owner {
+		f.run(fl, func() (any, error) { return f.fn(c) })
+	}
+	<-fl.done
+	return fl.result()
+}
+
+// start returns the singleton construction in progress, or a new one if there is none, in
+// which case owner is set and the caller must run it. Once the object is constructed, the
+// returned flight is already done. The lock is never held while the factory runs, so a
+// hung construction doesn't block callers that only wait for it with a timeout.
+func (f *factory) start() (fl *flight, owner bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.built {
+		fl = &flight{done: make(chan struct{}), obj: f.obj}
+		close(fl.done)
+		return fl, false
+	}
+	if f.flight != nil {
+		return f.flight, false
+	}
+
+	f.flight = &flight{done: make(chan struct{})}
+	return f.flight, true
+}
+
+// run constructs the object of fl with construct, caching it on success, and releases
+// everyone waiting for fl. A panic raised by construct is recorded in fl.
+func (f *factory) run(fl *flight, construct func() (any, error)) {
+	defer func() {
+		fl.panicked = recover()
+
+		f.lock.Lock()
+		if fl.err == nil && fl.panicked == nil {
+			f.obj, f.built = fl.obj, true
+		}
+		f.flight = nil
+		f.lock.Unlock()
+
+		close(fl.done)
+	}()
+
+	fl.obj, fl.err = construct()
+}
+
+// result returns the outcome of a finished construction, raising its panic again on the
+// calling goroutine.
+func (fl *flight) result() (any, error) {
+	if fl.panicked != nil {
+		panic(fl.panicked)
+	}
+	if fl.err != nil {
+		return nil, fl.err
+	}
+	return fl.obj, nil
+}
+
+// cached returns the singleton object if it has already been constructed.
+func (f *factory) cached() (any, bool) {
+	if f.scope == Transient {
+		return nil, false
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.obj, f.built
+}
+
+// constructed returns obj, or for factory bindings the constructed singleton, or nil if
+// there is none yet.
+func constructed(obj any) any {
+	if f, ok := obj.(*factory); ok {
+		obj, _ = f.cached()
+	}
+	return obj
+}
+
+// isFactory reports whether obj is a factory binding.
+func isFactory(obj any) bool {
+	_, ok := obj.(*factory)
+	return ok
+}
+
+// BindFactory binds a factory that constructs the object on resolve instead of up front.
+//
+// Singleton factories construct the object once, on first resolve, even under concurrent
+// resolves. Transient factories construct a new object on every resolve.
+func (c *container) BindFactory(name string, fn Factory, scope FactoryScope) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.bind(name, &factory{fn: fn, scope: scope})
+}
+
+// BindEnv binds name to the value of the environment variable envVar, read the first time
+// name is resolved. Resolving fails while the variable is empty, and succeeds once it is set.
+func (c *container) BindEnv(name, envVar string) {
+	c.BindFactory(name, func(Container) (any, error) {
+		val := os.Getenv(envVar)
+		if val == `` {
+			return nil, fmt.Errorf(`container: environment variable [%s] is empty`, envVar)
+		}
+		return val, nil
+	}, Singleton)
+}
+
+// construct returns the object of the factory bound under name, giving up once the bind
+// timeout has passed. The error names the innermost construction still in progress, which is
+// usually the one that hangs. A panic raised by the factory is raised again on the calling goroutine.
+func (c *container) construct(name string, f *factory) (any, error) {
+	if c.bindTimeout <= 0 || f.scope == Transient {
+		return f.get(c)
+	}
+
+	fl, owner := f.start()
+	if owner {
+		c.constructing.push(name)
+		go f.run(fl, func() (any, error) {
+			defer c.constructing.pop(name)
+			return f.fn(c)
+		})
+	}
+
+	select {
+	case <-fl.done:
+		return fl.result()
+	case <-c.clock.After(c.bindTimeout):
+		c.logWarning(name, `module %s not constructed within %s`, name, c.bindTimeout)
+		if stalled := c.constructing.stalled(); stalled != `` && stalled != name {
+			return nil, fmt.Errorf(`%w, binding [%s] not constructed within %s, stalled constructing [%s]`, ErrBindTimeout, name, c.bindTimeout, stalled)
+		}
+		return nil, fmt.Errorf(`%w, binding [%s] not constructed within %s`, ErrBindTimeout, name, c.bindTimeout)
+	}
+}
+
+// ResolveWithContext resolves the named object like Resolve, but returns an error instead of
+// panicking and stops waiting for a factory once ctx is done.
+//
+// The same ctx bounds every factory constructed along the way: resolves made by a factory
+// through the Container it is given share the deadline, so the whole construction chain
+// fits in one budget. When ctx is done first, the error names the binding whose
+// construction stalled, the construction keeps running in the background and its result
+// is kept for later resolves. Objects that are already constructed are returned immediately.
+func (c *container) ResolveWithContext(ctx context.Context, name string) (any, error) {
+	return c.resolveChained(ctx, &resolveChain{}, name)
+}
+
+// resolveChain tracks factory constructions in progress, for one ResolveWithContext call or,
+// with a bind timeout, for the whole container.
+type resolveChain struct {
+	lock    sync.Mutex
+	pending []string
+}
+
+func (r *resolveChain) push(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.pending = append(r.pending, name)
+}
+
+func (r *resolveChain) pop(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i := len(r.pending) - 1; i >= 0; i-- {
+		if r.pending[i] == name {
+			r.pending = append(r.pending[:i], r.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// stalled returns the innermost construction still in progress.
+func (r *resolveChain) stalled() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.pending) == 0 {
+		return ``
+	}
+	return r.pending[len(r.pending)-1]
+}
+
+// chainContainer is the Container handed to factories constructed by ResolveWithContext, so
+// their own resolves are bounded by the same context.
+type chainContainer struct {
+	*container
+	ctx   context.Context
+	chain *resolveChain
+}
+
+func (cc *chainContainer) Resolve(name string) any {
+	obj, err := cc.TryResolve(name)
+	if err != nil {
+		cc.fail(err)
+		return nil
+	}
+	return obj
+}
+
+func (cc *chainContainer) TryResolve(name string) (any, error) {
+	return cc.container.resolveChained(cc.ctx, cc.chain, name)
+}
+
+// resolveChained resolves name, constructing factories with a Container bound to ctx and chain.
+func (c *container) resolveChained(ctx context.Context, chain *resolveChain, name string) (any, error) {
+	obj, err := c.lookup(name)
+	if errors.Is(err, ErrModuleNotFound) && c.parent != nil {
+		return c.parent.resolveChained(ctx, chain, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := obj.(*factory)
+	if !ok {
+		return obj, nil
+	}
+	if obj, ok := f.cached(); ok {
+		return obj, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(`container: constructing [%s] interrupted: %w`, name, err)
+	}
+
+	cc := &chainContainer{container: c, ctx: ctx, chain: chain}
+	fl, owner := &flight{done: make(chan struct{})}, true
+	if f.scope != Transient {
+		fl, owner = f.start()
+	}
+	if owner {
+		chain.push(name)
+		run := func() (any, error) {
+			defer chain.pop(name)
+			return f.fn(cc)
+		}
+		if f.scope == Transient {
+			go runTransient(fl, run)
+		} else {
+			go f.run(fl, run)
+		}
+	}
+
+	select {
+	case <-fl.done:
+		// a failed resolve by the factory panics with an error, which is returned instead
+		if e, ok := fl.panicked.(error); ok {
+			return nil, fmt.Errorf(`container: constructing [%s] failed: %w`, name, e)
+		}
+		obj, err := fl.result()
+		if err != nil {
+			return nil, fmt.Errorf(`container: constructing [%s] failed: %w`, name, err)
+		}
+		return obj, nil
+	case <-ctx.Done():
+		if stalled := chain.stalled(); stalled != `` && stalled != name {
+			return nil, fmt.Errorf(`container: constructing [%s] interrupted, stalled constructing [%s]: %w`, name, stalled, ctx.Err())
+		}
+		return nil, fmt.Errorf(`container: constructing [%s] interrupted: %w`, name, ctx.Err())
+	}
+}
+
+// runTransient runs a transient construction, which nobody else waits for, recording its
+// outcome in fl.
+func runTransient(fl *flight, construct func() (any, error)) {
+	defer func() {
+		fl.panicked = recover()
+		close(fl.done)
+	}()
+
+	fl.obj, fl.err = construct()
+}
+
+// ResolveAndBind resolves resolveName and binds the result under bindName, returning it.
+func (c *container) ResolveAndBind(resolveName, bindName string) (any, error) {
+	obj, err := c.TryResolve(resolveName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Bind(bindName, obj)
+
+	return obj, nil
+}
+
+// Memoize replaces the factory binding under name with the object it resolves to, so every
+// later resolve returns that same object. Bindings that are not factories are left as is.
+// Once the container is frozen it returns ErrContainerFrozen, as its bindings can't change.
+func (c *container) Memoize(name string) error {
+	c.lock.Lock()
+	frozen := c.frozen
+	c.lock.Unlock()
+	if frozen {
+		return fmt.Errorf(`%w, memoizing [%s] refused`, ErrContainerFrozen, name)
+	}
+
+	obj, err := c.TryResolve(name)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.frozen {
+		return fmt.Errorf(`%w, memoizing [%s] refused`, ErrContainerFrozen, name)
+	}
+	if current, _ := c.store.Get(c.key(name)); isFactory(current) {
+		c.store.Set(c.key(name), obj)
+	}
+
+	return nil
+}
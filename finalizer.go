@@ -0,0 +1,66 @@
+package container
+
+import (
+	"fmt"
+	"sync"
+)
+
+// finalizer is a cleanup registered with BindWithFinalizer, run at most once.
+type finalizer struct {
+	name string
+	once sync.Once
+	fn   func()
+}
+
+// BindWithFinalizer binds obj under name like Bind and registers finalize as a best-effort
+// cleanup, such as flushing a local write-ahead log, that runs even when the shutdown isn't
+// graceful.
+//
+// finalize runs at most once: after ShutdownAll, right before a module panic crashes the
+// process when panics are not recovered, or from FinalizeOnPanic. It is not guaranteed to
+// run at all when the process is killed outright, for example by SIGKILL or os.Exit.
+func (c *container) BindWithFinalizer(name string, obj any, finalize func()) {
+	c.Bind(name, obj)
+
+	f := &finalizer{name: name, fn: finalize}
+
+	c.lock.Lock()
+	c.finalizers = append(c.finalizers, f)
+	c.lock.Unlock()
+}
+
+// FinalizeOnPanic runs the finalizers registered with BindWithFinalizer if the goroutine it
+// is deferred on is panicking, and then lets the panic continue. Defer it at the top of main:
+//
+//	defer c.FinalizeOnPanic()
+func (c *container) FinalizeOnPanic() {
+	if r := recover(); r != nil {
+		c.runFinalizers()
+		panic(r)
+	}
+}
+
+// runFinalizers runs every finalizer that hasn't run yet, in reverse registration order.
+func (c *container) runFinalizers() {
+	c.lock.RLock()
+	finalizers := append([]*finalizer{}, c.finalizers...)
+	c.lock.RUnlock()
+
+	for i := len(finalizers) - 1; i >= 0; i-- {
+		c.finalize(finalizers[i])
+	}
+}
+
+// finalize runs f unless it already ran. A panicking finalizer is logged, so the remaining
+// finalizers still run.
+func (c *container) finalize(f *finalizer) {
+	f.once.Do(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				c.logFailure(f.name, ``, fmt.Errorf(`container: module [%s] finalizer panicked: %v`, f.name, r))
+			}
+		}()
+
+		f.fn()
+	})
+}
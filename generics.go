@@ -0,0 +1,205 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// BindType binds obj under the name of type T, so it can be resolved with ResolveType.
+//
+// It panics if obj cannot be assigned to T, which catches a mismatched interface
+// binding at wiring time instead of at first use.
+func BindType[T any](c Container, obj any) {
+	if _, ok := obj.(T); !ok {
+		panic(fmt.Sprintf(`container: %T is not assignable to %s, binding failed`, obj, typeKey[T]()))
+	}
+	c.Bind(typeKey[T](), obj)
+}
+
+// ResolveType resolves the object bound with BindType for type T.
+//
+// Failures are handed to the handler set with WithErrorHandler like those of Resolve, in
+// which case the zero T is returned, and panic otherwise.
+func ResolveType[T any](c Container) T {
+	var zero T
+	obj, err := c.TryResolve(typeKey[T]())
+	if err != nil {
+		fail(c, err)
+		return zero
+	}
+
+	typed, ok := obj.(T)
+	if !ok {
+		fail(c, fmt.Errorf(`container: %T is not assignable to %s, resolving failed`, obj, typeKey[T]()))
+		return zero
+	}
+	return typed
+}
+
+// fail hands err to the error handler of c like the container's own panicking API, and
+// panics with it when c has no handler or isn't a container created by NewContainer.
+func fail(c Container, err error) {
+	if f, ok := c.(interface{ fail(err error) }); ok {
+		f.fail(err)
+		return
+	}
+	panic(err)
+}
+
+// ResolveOr resolves the named object as a T, or returns def if nothing usable is bound
+// under name or it is not a T. It never panics.
+func ResolveOr[T any](c Container, name string, def T) T {
+	obj, err := c.TryResolve(name)
+	if err != nil {
+		return def
+	}
+
+	typed, ok := obj.(T)
+	if !ok {
+		return def
+	}
+	return typed
+}
+
+// ResolveInterfaceAs resolves the object bound under name and returns it as the interface T,
+// such as Runnable, whatever its concrete type. An error is returned if nothing is bound
+// under name or the object doesn't implement T.
+func ResolveInterfaceAs[T any](c Container, name string) (T, error) {
+	var zero T
+	obj, err := c.TryResolve(name)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := obj.(T)
+	if !ok {
+		return zero, fmt.Errorf(`container: module [%s] is %T, which does not implement %s`, name, obj, typeKey[T]())
+	}
+	return typed, nil
+}
+
+// Bind binds obj under name like Container.Bind, with obj checked to be a T at compile time.
+func Bind[T any](c Container, name string, obj T) {
+	c.Bind(name, obj)
+}
+
+// Resolve resolves the object bound under name as a T. An error is returned if nothing is
+// bound under name, construction fails or the object is not a T, so a mismatched binding is
+// reported at the call site instead of failing a type assertion.
+func Resolve[T any](c Container, name string) (T, error) {
+	var zero T
+	obj, err := c.TryResolve(name)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := obj.(T)
+	if !ok {
+		return zero, fmt.Errorf(`container: module [%s] is %T, not %s`, name, obj, typeKey[T]())
+	}
+	return typed, nil
+}
+
+// MustResolve resolves the object bound under name as a T like Resolve, but panics with the error instead.
+func MustResolve[T any](c Container, name string) T {
+	typed, err := Resolve[T](c, name)
+	if err != nil {
+		panic(err)
+	}
+	return typed
+}
+
+// ResolveFactory resolves the object bound under name as a T, whether it was bound eagerly or
+// through BindFactory, in which case the value is constructed or taken from the factory's
+// cache. An error is returned if nothing is bound under name, construction fails or the
+// value is not a T.
+func ResolveFactory[T any](c Container, name string) (T, error) {
+	return Resolve[T](c, name)
+}
+
+// BindInterface binds impl under the interface type ifacePtr points to, given as a typed nil
+// such as (*Cache)(nil). It is equivalent to BindType for that interface.
+//
+// It panics if ifacePtr is not a pointer to an interface or impl does not implement it.
+func (c *container) BindInterface(ifacePtr any, impl any) {
+	iface := interfaceOf(ifacePtr)
+	if impl == nil || !reflect.TypeOf(impl).Implements(iface) {
+		panic(fmt.Sprintf(`container: %T does not implement %s, binding failed`, impl, typeName(iface)))
+	}
+	c.Bind(typeName(iface), impl)
+}
+
+// ResolveInterface resolves the object bound under the interface type ifacePtr points to.
+func (c *container) ResolveInterface(ifacePtr any) any {
+	return c.Resolve(typeName(interfaceOf(ifacePtr)))
+}
+
+// interfaceOf returns the interface type ifacePtr points to, panicking if it isn't a pointer to an interface.
+func interfaceOf(ifacePtr any) reflect.Type {
+	t := reflect.TypeOf(ifacePtr)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf(`container: %T is not a pointer to an interface`, ifacePtr))
+	}
+	return t.Elem()
+}
+
+// typeKey returns the binding name used for type T.
+func typeKey[T any]() string {
+	return typeName(reflect.TypeFor[T]())
+}
+
+// typeName returns the fully qualified name of t, falling back to its string form for unnamed types.
+func typeName(t reflect.Type) string {
+	if t.Name() != `` && t.PkgPath() != `` {
+		return t.PkgPath() + `.` + t.Name()
+	}
+	return t.String()
+}
+
+// Config returns the global config registered under key as a T.
+//
+// It panics if no config is registered under key or if it is not a T, unless an error
+// handler is set with WithErrorHandler, which receives the failure while the zero T is
+// returned.
+func Config[T any](c Container, key string) T {
+	var zero T
+	cfg, err := c.TryGlobalConfig(key)
+	if err != nil {
+		fail(c, err)
+		return zero
+	}
+
+	typed, ok := cfg.(T)
+	if !ok {
+		fail(c, fmt.Errorf(`container: config [%s] is %T, not %s`, key, cfg, typeKey[T]()))
+		return zero
+	}
+	return typed
+}
+
+// ConfigOr returns the global config registered under key as a T, or the zero value of T
+// if none is registered or it is not a T. It never panics.
+func ConfigOr[T any](c Container, key string) T {
+	cfg, _ := c.LookupGlobalConfig(key)
+	typed, _ := cfg.(T)
+	return typed
+}
+
+// ModulesImplementing returns the names of all bindings that implement T, sorted by name.
+//
+// Factory bindings are only considered once they are constructed, and the bindings of the
+// container itself are skipped.
+func ModulesImplementing[T any](c Container) []string {
+	w, ok := c.(bindingWalker)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0)
+	for _, b := range w.walkBindings() {
+		if _, ok := b.Obj.(T); ok {
+			names = append(names, b.Name)
+		}
+	}
+	return names
+}
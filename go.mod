@@ -2,7 +2,11 @@ module github.com/recodextech/container
 
 go 1.23.2
 
-require github.com/wgarunap/goconf v0.9.0
+require (
+	github.com/wgarunap/goconf v0.9.0
+	go.opentelemetry.io/otel v1.35.0
+	go.opentelemetry.io/otel/trace v1.35.0
+)
 
 require (
 	github.com/caarlos0/env/v11 v11.3.1 // indirect
@@ -0,0 +1,85 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependencyEdge is a dependency of module From on module To. Soft is set for dependencies
+// declared with SoftDependent.
+type DependencyEdge struct {
+	From string
+	To   string
+	Soft bool
+}
+
+// DependencyGraph is the wiring of a container: every binding and the dependencies declared
+// between them.
+type DependencyGraph struct {
+	Nodes []string
+	Edges []DependencyEdge
+}
+
+// Bindings returns the names of every binding except the container itself, sorted by name.
+func (c *container) Bindings() []string {
+	return c.knownBindings()
+}
+
+// DependencyGraph returns the bindings and the dependencies they declare, with Dependent,
+// SoftDependent or the DependsOn bind option, sorted by name.
+//
+// Hard dependencies on names that are not bound are included, so missing wiring shows up,
+// while soft dependencies on them are left out. Factory bindings only declare their
+// dependencies once they are constructed.
+func (c *container) DependencyGraph() DependencyGraph {
+	g := DependencyGraph{Nodes: c.knownBindings(), Edges: make([]DependencyEdge, 0)}
+
+	for _, b := range c.walkBindings() {
+		seen := make(map[string]bool)
+		for _, dep := range c.hardDependencies(b.Name) {
+			if !seen[dep] {
+				seen[dep] = true
+				g.Edges = append(g.Edges, DependencyEdge{From: b.Name, To: dep})
+			}
+		}
+
+		d, ok := b.Obj.(SoftDependent)
+		if !ok {
+			continue
+		}
+		for _, dep := range c.keys(d.SoftDependsOn()) {
+			if !seen[dep] && c.isBound(dep) {
+				seen[dep] = true
+				g.Edges = append(g.Edges, DependencyEdge{From: b.Name, To: dep, Soft: true})
+			}
+		}
+	}
+
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+// DOT renders the graph in the Graphviz DOT language, with soft dependencies dashed, for
+// example to render it with `dot -Tsvg`.
+func (g DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph container {\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "\t%q;\n", node)
+	}
+	for _, e := range g.Edges {
+		if e.Soft {
+			fmt.Fprintf(&b, "\t%q -> %q [style=dashed];\n", e.From, e.To)
+			continue
+		}
+		fmt.Fprintf(&b, "\t%q -> %q;\n", e.From, e.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package container
+
+// BindToGroup appends obj to the named group.
+//
+// Unlike Bind, binding to a group never replaces earlier objects, so independently
+// registered modules can contribute to the same group.
+func (c *container) BindToGroup(group string, obj any) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.checkFrozen(group)
+	c.groups[group] = append(c.groups[group], obj)
+}
+
+// ResolveGroup returns the objects of the named group in the order they were bound.
+//
+// An empty slice is returned for a group nothing was bound to.
+func (c *container) ResolveGroup(group string) []any {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	objs := make([]any, len(c.groups[group]))
+	copy(objs, c.groups[group])
+	return objs
+}
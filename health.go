@@ -0,0 +1,187 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HealthCheckable interface is used by modules that can report their overall health.
+type HealthCheckable interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// LivenessChecker interface is used by modules that can report whether they are alive.
+//
+// A failing liveness check means the module is broken and the process should be restarted.
+type LivenessChecker interface {
+	LivenessCheck(ctx context.Context) error
+}
+
+// ReadinessChecker interface is used by modules that can report whether they are ready.
+//
+// A failing readiness check means the module should not receive traffic yet, for example
+// while it is warming up, even though it is alive.
+type ReadinessChecker interface {
+	ReadinessCheck(ctx context.Context) error
+}
+
+// HealthStatus is the outcome of a health check.
+type HealthStatus string
+
+const (
+	HealthUp   HealthStatus = `up`
+	HealthDown HealthStatus = `down`
+)
+
+// ModuleHealth is the result of a single module's health check.
+type ModuleHealth struct {
+	Status  HealthStatus
+	Err     error
+	Latency time.Duration
+}
+
+// HealthReport aggregates the health checks of all modules. Status is HealthDown if any module is down.
+type HealthReport struct {
+	Status  HealthStatus
+	Modules map[string]ModuleHealth
+}
+
+// Health runs the health check of every module implementing HealthCheckable and returns
+// the result per module. A nil result means the module is healthy.
+func (c *container) Health(ctx context.Context) map[string]error {
+	return errorsOf(c.checkTimed(ctx, healthCheckOf))
+}
+
+// CheckHealth runs the health check of every module implementing HealthCheckable like
+// Health, and reports the status and latency of each along with the overall status.
+func (c *container) CheckHealth(ctx context.Context) HealthReport {
+	report := HealthReport{Status: HealthUp, Modules: c.checkTimed(ctx, healthCheckOf)}
+	for _, h := range report.Modules {
+		if h.Status == HealthDown {
+			report.Status = HealthDown
+		}
+	}
+	return report
+}
+
+// healthCheckOf selects the HealthCheck of modules implementing HealthCheckable.
+func healthCheckOf(obj any) (func(context.Context) error, bool) {
+	h, ok := obj.(HealthCheckable)
+	if !ok {
+		return nil, false
+	}
+	return h.HealthCheck, true
+}
+
+// Liveness runs the liveness check of every module implementing LivenessChecker and
+// returns the result per module. A nil result means the module is alive.
+func (c *container) Liveness(ctx context.Context) map[string]error {
+	return c.check(ctx, func(obj any) (func(context.Context) error, bool) {
+		l, ok := obj.(LivenessChecker)
+		if !ok {
+			return nil, false
+		}
+		return l.LivenessCheck, true
+	})
+}
+
+// Readiness runs the readiness check of every module implementing ReadinessChecker and
+// returns the result per module. A nil result means the module is ready.
+func (c *container) Readiness(ctx context.Context) map[string]error {
+	return c.check(ctx, func(obj any) (func(context.Context) error, bool) {
+		r, ok := obj.(ReadinessChecker)
+		if !ok {
+			return nil, false
+		}
+		return r.ReadinessCheck, true
+	})
+}
+
+// check runs the check selected by checkOf like checkTimed and returns the error per module.
+func (c *container) check(ctx context.Context, checkOf func(obj any) (func(context.Context) error, bool)) map[string]error {
+	return errorsOf(c.checkTimed(ctx, checkOf))
+}
+
+// errorsOf returns the error of each module health.
+func errorsOf(results map[string]ModuleHealth) map[string]error {
+	errs := make(map[string]error, len(results))
+	for name, h := range results {
+		errs[name] = h.Err
+	}
+	return errs
+}
+
+// checkTimed runs the check selected by checkOf on every enabled module that has one,
+// except modules bound with SkipHealthCheck, timing each.
+//
+// Checks run concurrently, at most healthParallelism at a time. Once ctx is done, or the
+// health timeout has passed, every check that hasn't returned is recorded as timed out.
+func (c *container) checkTimed(ctx context.Context, checkOf func(obj any) (func(context.Context) error, bool)) map[string]ModuleHealth {
+	if c.healthTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = c.withTimeout(ctx, c.healthTimeout)
+		defer cancel()
+	}
+
+	type result struct {
+		name string
+		err  error
+		took time.Duration
+	}
+
+	checks := make(map[string]func(context.Context) error)
+	pending := make(map[string]bool)
+	for _, b := range c.walkBindings() {
+		if c.isDisabled(b.Name) || c.options(b.Name).skipHealthCheck {
+			continue
+		}
+		if check, ok := checkOf(b.Obj); ok {
+			checks[b.Name] = check
+			pending[b.Name] = true
+		}
+	}
+
+	done := make(chan result, len(checks))
+	sem := make(chan struct{}, max(c.healthParallelism, 1))
+	for name, check := range checks {
+		go func() {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			began := c.clock.Now()
+			err := c.call(name, PhaseHealthCheck, func() error { return check(ctx) })
+			done <- result{name: name, err: err, took: c.clock.Now().Sub(began)}
+		}()
+	}
+
+	began := c.clock.Now()
+	results := make(map[string]ModuleHealth, len(checks))
+	for len(pending) > 0 {
+		select {
+		case r := <-done:
+			results[r.name] = moduleHealth(r.err, r.took)
+			delete(pending, r.name)
+		case <-ctx.Done():
+			for name := range pending {
+				err := fmt.Errorf(`container: module [%s] health check timed out: %w`, name, ctx.Err())
+				results[name] = moduleHealth(err, c.clock.Now().Sub(began))
+			}
+			return results
+		}
+	}
+
+	return results
+}
+
+// moduleHealth returns the health of a module whose check returned err after took.
+func moduleHealth(err error, took time.Duration) ModuleHealth {
+	if err != nil {
+		return ModuleHealth{Status: HealthDown, Err: err, Latency: took}
+	}
+	return ModuleHealth{Status: HealthUp, Latency: took}
+}
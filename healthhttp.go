@@ -0,0 +1,117 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// HealthHandler returns an http.Handler for Kubernetes style probes.
+//
+// /healthz runs the health checks with CheckHealth and answers 200 when every module is up
+// and 503 otherwise, with the report as JSON. /readyz answers 200 while Ready is true and
+// 503 otherwise, so it flips to not ready as soon as shutdown begins.
+func (c *container) HealthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(`/healthz`, func(w http.ResponseWriter, r *http.Request) {
+		report := c.CheckHealth(r.Context())
+
+		code := http.StatusOK
+		if report.Status != HealthUp {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, healthBody(report))
+	})
+	mux.HandleFunc(`/readyz`, func(w http.ResponseWriter, r *http.Request) {
+		if !c.Ready() || c.IsShuttingDown() {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]HealthStatus{`status`: HealthDown})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]HealthStatus{`status`: HealthUp})
+	})
+	return mux
+}
+
+// moduleHealthBody is the JSON form of a ModuleHealth.
+type moduleHealthBody struct {
+	Status  HealthStatus `json:"status"`
+	Latency string       `json:"latency"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// healthBody returns the JSON form of a HealthReport.
+func healthBody(report HealthReport) any {
+	modules := make(map[string]moduleHealthBody, len(report.Modules))
+	for name, h := range report.Modules {
+		body := moduleHealthBody{Status: h.Status, Latency: h.Latency.String()}
+		if h.Err != nil {
+			body.Error = h.Err.Error()
+		}
+		modules[name] = body
+	}
+
+	return struct {
+		Status  HealthStatus                `json:"status"`
+		Modules map[string]moduleHealthBody `json:"modules"`
+	}{Status: report.Status, Modules: modules}
+}
+
+// writeJSON writes body as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set(`Content-Type`, `application/json`)
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// HealthServer is a module serving the container's HealthHandler on Addr, so the probes
+// start and stop with the rest of the modules.
+type HealthServer struct {
+	Addr string
+
+	lock   sync.Mutex
+	server *http.Server
+}
+
+// NewHealthServer returns a HealthServer listening on addr, such as ":8081".
+func NewHealthServer(addr string) *HealthServer {
+	return &HealthServer{Addr: addr}
+}
+
+func (s *HealthServer) Init(c Container) error {
+	h, ok := c.(interface{ HealthHandler() http.Handler })
+	if !ok {
+		return fmt.Errorf(`container: %T does not serve health checks`, c)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.server = &http.Server{Addr: s.Addr, Handler: h.HealthHandler()}
+	return nil
+}
+
+func (s *HealthServer) Run() error {
+	s.lock.Lock()
+	server := s.server
+	s.lock.Unlock()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// StopContext shuts the server down gracefully, waiting for in-flight probes until ctx is done.
+func (s *HealthServer) StopContext(ctx context.Context) error {
+	s.lock.Lock()
+	server := s.server
+	s.lock.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
@@ -0,0 +1,121 @@
+package container
+
+// OnStarted registers a callback that runs once Start has launched every module.
+//
+// Callbacks run in registration order on the goroutine that called Start.
+func (c *container) OnStarted(fn func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.onStarted = append(c.onStarted, fn)
+}
+
+// runStarted runs the callbacks registered with OnStarted.
+func (c *container) runStarted() {
+	c.lock.Lock()
+	hooks := append([]func(){}, c.onStarted...)
+	c.lock.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// OnModuleFailed registers a callback that runs whenever a module enters StateFailed, with
+// the module name and the failure, whether or not the container shuts down afterwards.
+//
+// Callbacks run in registration order on the goroutine where the module failed.
+func (c *container) OnModuleFailed(fn func(name string, err error)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.onFailed = append(c.onFailed, fn)
+}
+
+// markFailed records the module as failed and runs the callbacks registered with OnModuleFailed.
+func (c *container) markFailed(name string, err error) {
+	c.lock.Lock()
+	c.states[name] = StateFailed
+	hooks := append([]func(string, error){}, c.onFailed...)
+	c.lock.Unlock()
+	c.emit(name, StateFailed, err)
+
+	for _, fn := range hooks {
+		fn(name, err)
+	}
+}
+
+// OnShutdownProgress registers a callback that ShutdownAll runs before stopping each module,
+// with the number of modules already stopped, the total to stop and the module stopped now.
+func (c *container) OnShutdownProgress(fn func(stopped, total int, current string)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.onProgress = append(c.onProgress, fn)
+}
+
+// shutdownProgress runs the callbacks registered with OnShutdownProgress.
+func (c *container) shutdownProgress(stopped, total int, current string) {
+	c.lock.Lock()
+	hooks := append([]func(int, int, string){}, c.onProgress...)
+	c.lock.Unlock()
+
+	for _, fn := range hooks {
+		fn(stopped, total, current)
+	}
+}
+
+// OnShutdownComplete registers a callback that ShutdownAll runs once every module's Stop has
+// returned and every Run has exited, right before the container is marked as stopped.
+//
+// Callbacks run in registration order, once per shutdown. With callbacks registered,
+// ShutdownAll waits for Run to exit, for at most the stop timeout if one is set.
+func (c *container) OnShutdownComplete(fn func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.onComplete = append(c.onComplete, fn)
+}
+
+// runShutdownComplete waits for the Run goroutines and runs the callbacks registered with
+// OnShutdownComplete, unless they already ran for this shutdown.
+func (c *container) runShutdownComplete() {
+	c.lock.Lock()
+	hooks := append([]func(){}, c.onComplete...)
+	ran := c.completed
+	c.completed = true
+	c.lock.Unlock()
+
+	if ran || len(hooks) == 0 {
+		return
+	}
+
+	if !c.waitRuns() {
+		c.logWarning(``, `modules still running after %s, completing shutdown anyway`, c.stopTimeout)
+	}
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// waitRuns waits for the Run goroutines of the started modules to exit, giving up after the
+// stop timeout if one is set. It reports whether they all exited.
+func (c *container) waitRuns() bool {
+	done := make(chan struct{})
+	go func() {
+		c.running.Wait()
+		close(done)
+	}()
+
+	if c.stopTimeout <= 0 {
+		<-done
+		return true
+	}
+
+	select {
+	case <-done:
+		return true
+	case <-c.clock.After(c.stopTimeout):
+		return false
+	}
+}
@@ -0,0 +1,303 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// InitableCtx interface is used by modules whose Init honors cancellation. When a module
+// implements it, InitContext is called instead of Init.
+type InitableCtx interface {
+	InitContext(ctx context.Context, c Container) error
+}
+
+// maxInitDiscovered caps how many modules may be registered by other modules during a
+// single Init pass, which stops a module that registers a new module on every Init.
+const maxInitDiscovered = 1000
+
+func (c *container) Init(modules ...string) {
+	if err := c.InitE(modules...); err != nil {
+		c.fail(err)
+	}
+}
+
+// InitE initializes modules like Init but returns the first failure instead of panicking.
+//
+// Modules implementing Configurable are configured first, all of them before any module
+// is initialized, so mutually aware modules can exchange references before completing setup.
+//
+// Modules bound by another module's Init, such as the plugins of a plugin host, are picked
+// up and initialized in the same pass, right after the module that bound them.
+func (c *container) InitE(modules ...string) error {
+	_, err := c.initAll(context.Background(), modules)
+	return err
+}
+
+// InitWithContext initializes modules like InitE, but can be aborted through ctx.
+//
+// Cancellation is checked between modules and passed on to modules implementing
+// InitableCtx. When the init is cancelled or fails, the modules it already initialized are
+// rolled back by stopping them in reverse order, and ctx.Err() or the failure is returned.
+func (c *container) InitWithContext(ctx context.Context, modules ...string) error {
+	done, err := c.initAll(ctx, modules)
+	if err == nil {
+		return nil
+	}
+
+	c.rollback(done)
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// rollback stops the stoppable modules among modules in reverse order, undoing a partial init.
+func (c *container) rollback(modules []string) {
+	for i := len(modules) - 1; i >= 0; i-- {
+		module := modules[i]
+		if !isStoppable(c.binding(module)) {
+			continue
+		}
+
+		c.logger.Printf(`module %s rolling back...`, module)
+		if err := c.call(module, PhaseStop, func() error { return c.stop(module) }); err != nil {
+			c.logFailure(module, PhaseStop, err)
+		}
+	}
+}
+
+// initAll runs both init passes over modules and the modules they register, returning the
+// modules it initialized.
+func (c *container) initAll(ctx context.Context, modules []string) (done []string, err error) {
+	c.markBooted()
+
+	c.lock.RLock()
+	deadline := c.bootedAt.Add(c.startupDeadline)
+	c.lock.RUnlock()
+	if c.startupDeadline > 0 && deadline.After(c.clock.Now()) {
+		parent := ctx
+		var cancel context.CancelFunc
+		ctx, cancel = c.withDeadline(ctx, deadline)
+		defer cancel()
+
+		initCtx := ctx
+		defer func() {
+			if err != nil && initCtx.Err() != nil && parent.Err() == nil {
+				err = c.initTimedOut(modules, done, err)
+			}
+		}()
+	}
+
+	ctx, span := c.startSpan(c.bootstrapContext(ctx), `container.init`, attribute.StringSlice(`container.modules`, modules))
+	defer func() {
+		endSpan(span, err)
+		if err != nil {
+			c.endBootstrap(err)
+		}
+	}()
+
+	known := c.bindingNames()
+	done = make([]string, 0)
+
+	sorted, err := c.sortModules(modules)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make([]string, 0, len(sorted))
+	for _, name := range sorted {
+		if c.isDisabled(name) {
+			c.logger.Printf(`module %s disabled, skipping init`, name)
+			continue
+		}
+		enabled = append(enabled, name)
+	}
+
+	for _, name := range enabled {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		if err := c.configureModule(name); err != nil {
+			return done, err
+		}
+	}
+
+	discovered := 0
+	for i := 0; i < len(enabled); i++ {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		if err := c.initModule(ctx, enabled[i]); err != nil {
+			return done, err
+		}
+		done = append(done, enabled[i])
+
+		added, err := c.discoverModules(known)
+		if err != nil {
+			return done, err
+		}
+		if discovered += len(added); discovered > maxInitDiscovered {
+			return done, fmt.Errorf(`container: more than %d modules registered during init, possible registration loop`, maxInitDiscovered)
+		}
+		for _, name := range added {
+			c.logger.Printf(`module %s registered by %s, initializing`, name, enabled[i])
+			if err := c.configureModule(name); err != nil {
+				return done, err
+			}
+		}
+
+		// initialize the new modules next, before the rest of the pass
+		enabled = append(enabled[:i+1], append(added, enabled[i+1:]...)...)
+	}
+
+	return done, nil
+}
+
+// bindingNames returns the set of names currently bound.
+func (c *container) bindingNames() map[string]bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	names := make(map[string]bool)
+	for _, name := range c.store.Keys() {
+		names[name] = true
+	}
+	return names
+}
+
+// discoverModules returns the enabled modules bound since known was taken that implement
+// Initable, InitableCtx or Configurable, in dependency order. Every new name is added to known, so each
+// binding is considered once.
+func (c *container) discoverModules(known map[string]bool) ([]string, error) {
+	fresh := make([]string, 0)
+	for name := range c.bindingNames() {
+		if known[name] {
+			continue
+		}
+		known[name] = true
+		fresh = append(fresh, name)
+	}
+	if len(fresh) == 0 {
+		return nil, nil
+	}
+	sort.Strings(fresh)
+
+	modules := make([]string, 0, len(fresh))
+	for _, name := range fresh {
+		if c.isDisabled(name) {
+			continue
+		}
+
+		m, err := c.module(name)
+		if err != nil {
+			c.markFailed(name, err)
+			return nil, err
+		}
+		_, initable := m.(Initable)
+		_, initableCtx := m.(InitableCtx)
+		_, configurable := m.(Configurable)
+		if initable || initableCtx || configurable {
+			modules = append(modules, name)
+		}
+	}
+
+	return c.sortModules(modules)
+}
+
+// configureModule runs the first initialization pass on a module implementing Configurable.
+func (c *container) configureModule(name string) error {
+	m, err := c.module(name)
+	if err != nil {
+		c.markFailed(name, err)
+		return err
+	}
+
+	cfg, ok := m.(Configurable)
+	if !ok {
+		return nil
+	}
+
+	if err := c.call(name, PhaseConfigure, func() error { return cfg.Configure(c) }); err != nil {
+		c.markFailed(name, err)
+		return err
+	}
+
+	return nil
+}
+
+// initModule runs the second initialization pass on a module.
+func (c *container) initModule(ctx context.Context, name string) (err error) {
+	ctx, span := c.startSpan(ctx, `container.init.module`, attribute.String(moduleAttr, name))
+	defer func() { endSpan(span, err) }()
+
+	c.renewModuleContext(name)
+
+	m, err := c.module(name)
+	if err != nil {
+		c.markFailed(name, err)
+		return err
+	}
+	if m == nil {
+		return nil
+	}
+
+	if c.autoInject && injectable(m) {
+		if err := c.Inject(m); err != nil {
+			c.markFailed(name, err)
+			return err
+		}
+	}
+
+	if err := c.checkConfigs(name, m); err != nil {
+		c.markFailed(name, err)
+		return err
+	}
+
+	c.setInitializing(name)
+	defer c.setInitializing(``)
+
+	began := c.clock.Now()
+
+	if in, ok := m.(InitableCtx); ok {
+		if err := c.call(name, PhaseInit, func() error { return in.InitContext(ctx, c) }); err != nil {
+			c.markFailed(name, err)
+			return err
+		}
+	} else if in, ok := m.(Initable); ok {
+		if err := c.call(name, PhaseInit, func() error { return in.Init(c) }); err != nil {
+			c.markFailed(name, err)
+			return err
+		}
+	}
+	c.lock.Lock()
+	c.initialized = append(c.initialized, name)
+	c.states[name] = StateInitialized
+	c.lock.Unlock()
+	c.publish(Event{Module: name, State: StateInitialized, Duration: c.clock.Now().Sub(began)})
+
+	return nil
+}
+
+// checkConfigs returns an error naming the configs required by a ConfigDependent module that are not set.
+func (c *container) checkConfigs(name string, m any) error {
+	d, ok := m.(ConfigDependent)
+	if !ok {
+		return nil
+	}
+
+	missing := make([]string, 0)
+	for _, key := range d.RequiresConfig() {
+		if _, ok := c.moduleConfig(key); !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf(`container: module [%s] requires configs [%s] that are not set, init failed`, name, strings.Join(missing, `, `))
+	}
+	return nil
+}
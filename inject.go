@@ -0,0 +1,55 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// injectTag is the struct tag naming the binding a field is populated from.
+const injectTag = `inject`
+
+// Inject populates the fields of target tagged with `inject:"name"` with the objects bound
+// under those names.
+//
+// Target must be a pointer to a struct and tagged fields must be exported. Fields without
+// the tag are left untouched.
+func (c *container) Inject(target any) error {
+	if !injectable(target) {
+		return fmt.Errorf(`container: cannot inject into %T, a pointer to a struct is required`, target)
+	}
+
+	v := reflect.ValueOf(target).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup(injectTag)
+		if !ok || name == `` {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf(`container: cannot inject [%s] into unexported field %s.%s`, name, t.Name(), field.Name)
+		}
+
+		obj, err := c.TryResolve(name)
+		if err != nil {
+			return fmt.Errorf(`container: injecting %s.%s failed: %w`, t.Name(), field.Name, err)
+		}
+
+		val := reflect.ValueOf(obj)
+		if !val.IsValid() {
+			continue
+		}
+		if !val.Type().AssignableTo(field.Type) {
+			return fmt.Errorf(`container: cannot inject [%s] of type %T into %s.%s of type %s`, name, obj, t.Name(), field.Name, field.Type)
+		}
+		v.Field(i).Set(val)
+	}
+
+	return nil
+}
+
+// injectable reports whether obj is a pointer to a struct, the only kind of object Inject accepts.
+func injectable(obj any) bool {
+	v := reflect.ValueOf(obj)
+	return v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Struct
+}
@@ -0,0 +1,184 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ReadySignaler interface is used by modules that need time after Run is called before they
+// can serve, such as to bind a port, run migrations or warm a cache. WaitReady blocks until
+// the module is ready, or returns an error if it won't become ready or ctx is done.
+//
+// The container only reports the module as started, and only starts the modules depending
+// on it, once WaitReady returns. ctx is cancelled when the module is stopped, and expires at
+// the startup deadline if one is set with WithStartupDeadline.
+type ReadySignaler interface {
+	WaitReady(ctx context.Context) error
+}
+
+// launcher starts modules once the modules they depend on are started and ready.
+type launcher struct {
+	c  *container
+	wg sync.WaitGroup
+
+	ready map[string]chan struct{} // closed once the module is started and ready, or won't be
+
+	lock sync.Mutex
+	up   map[string]bool // set before ready is closed for modules that became ready
+}
+
+// newLauncher returns a launcher for modules, which must be in dependency order.
+func newLauncher(c *container, modules []string) *launcher {
+	l := &launcher{
+		c:     c,
+		ready: make(map[string]chan struct{}, len(modules)),
+		up:    make(map[string]bool, len(modules)),
+	}
+	for _, module := range modules {
+		l.ready[module] = make(chan struct{})
+	}
+	return l
+}
+
+// add starts the module, right away if the modules it depends on are already ready and in
+// the background otherwise. Waiting for the module itself to become ready never holds up add.
+func (l *launcher) add(module string, m any) {
+	l.wg.Add(1)
+
+	if l.waiting(module) {
+		go func() {
+			defer l.wg.Done()
+			if l.begin(module, m) {
+				l.awaitReady(module, m)
+			}
+		}()
+		return
+	}
+
+	if !l.begin(module, m) {
+		l.wg.Done()
+		return
+	}
+	if _, ok := m.(ReadySignaler); !ok {
+		l.awaitReady(module, m)
+		l.wg.Done()
+		return
+	}
+	go func() {
+		defer l.wg.Done()
+		l.awaitReady(module, m)
+	}()
+}
+
+// wait blocks until every added module is started and ready, or won't be.
+func (l *launcher) wait() {
+	l.wg.Wait()
+}
+
+// waiting reports whether any module the module depends on isn't ready yet.
+func (l *launcher) waiting(module string) bool {
+	for _, dep := range l.c.dependenciesOf(module) {
+		ch, ok := l.ready[dep]
+		if !ok || dep == module {
+			continue
+		}
+		select {
+		case <-ch:
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// begin waits for the modules the module depends on and starts its Run. It reports false
+// if the module was not started, because a dependency didn't become ready or shutdown was
+// requested, in which case the module is marked StateCancelled.
+func (l *launcher) begin(module string, m any) bool {
+	c := l.c
+	for _, dep := range c.dependenciesOf(module) {
+		ch, ok := l.ready[dep]
+		if !ok || dep == module {
+			continue
+		}
+		<-ch
+		if !l.isUp(dep) {
+			c.cancelStart([]string{module})
+			l.finish(module, false)
+			return false
+		}
+	}
+
+	c.logger.Printf(`module %s starting...`, module)
+
+	if !c.markStarted(module) {
+		c.cancelStart([]string{module})
+		l.finish(module, false)
+		return false
+	}
+
+	c.running.Add(1)
+	go c.run(module, m)
+
+	return true
+}
+
+// awaitReady waits for a started module implementing ReadySignaler to become ready. A module
+// that fails to become ready outside of a shutdown fails like a failing Run.
+func (l *launcher) awaitReady(module string, m any) {
+	c := l.c
+	if err := c.waitReady(module, m); err != nil {
+		if !c.stopRequested() {
+			err = fmt.Errorf(`container: module [%s] did not become ready: %w`, module, err)
+			c.markFailed(module, err)
+			c.runFailed(module, err)
+		}
+		l.finish(module, false)
+		return
+	}
+
+	c.logger.Printf(`module %s started`, module)
+	l.finish(module, true)
+}
+
+// finish records whether the module became ready and releases the modules waiting for it.
+func (l *launcher) finish(module string, up bool) {
+	l.lock.Lock()
+	l.up[module] = up
+	l.lock.Unlock()
+
+	close(l.ready[module])
+}
+
+// isUp reports whether the module became ready. It must only be called once the module's
+// ready channel is closed.
+func (l *launcher) isUp(module string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.up[module]
+}
+
+// waitReady calls WaitReady on a module implementing ReadySignaler, with the module's
+// context bounded by the startup deadline while it hasn't passed. Modules started again
+// later, such as by RestartAll, are not bound by it.
+func (c *container) waitReady(module string, m any) error {
+	r, ok := m.(ReadySignaler)
+	if !ok {
+		return nil
+	}
+
+	ctx := c.ModuleContext(module)
+
+	c.lock.RLock()
+	deadline := c.bootedAt.Add(c.startupDeadline)
+	c.lock.RUnlock()
+	if c.startupDeadline > 0 && deadline.After(c.clock.Now()) {
+		var cancel context.CancelFunc
+		ctx, cancel = c.withDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	return c.call(module, PhaseRun, func() error { return r.WaitReady(ctx) })
+}
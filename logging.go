@@ -0,0 +1,153 @@
+package container
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// StructuredLogger is the part of a structured logger the container logs through. A
+// *slog.Logger satisfies it, as do adapters for loggers such as zap.
+type StructuredLogger interface {
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+const (
+	moduleLogAttr = `module`
+	phaseLogAttr  = `phase`
+)
+
+// structuredWriter forwards each line written by a *log.Logger to a StructuredLogger, as a
+// warning for lines starting with "warning: ", as an error for lines starting with
+// "container: " and as info otherwise. Lines about a module carry its name as an attribute.
+//
+// The container logs its own warnings and failures with an explicit level through
+// logWarning and logFailure; the prefixes cover what modules log through Logger.
+type structuredWriter struct {
+	logger StructuredLogger
+}
+
+func (w structuredWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if msg, ok := strings.CutPrefix(line, `warning: `); ok {
+			w.logger.Warn(msg, lineAttrs(msg)...)
+			continue
+		}
+		if strings.HasPrefix(line, `container: `) {
+			w.logger.Error(line)
+			continue
+		}
+		w.logger.Info(line, lineAttrs(line)...)
+	}
+	return len(p), nil
+}
+
+// lineAttrs returns the module attribute for a line starting with "module <name> ".
+func lineAttrs(line string) []any {
+	rest, ok := strings.CutPrefix(line, `module `)
+	if !ok {
+		return nil
+	}
+	module, _, ok := strings.Cut(rest, ` `)
+	if !ok {
+		return nil
+	}
+	return []any{moduleLogAttr, module}
+}
+
+// newStructuredLogger returns a *log.Logger writing every line to logger, without a prefix
+// or timestamp of its own.
+func newStructuredLogger(logger StructuredLogger) *log.Logger {
+	return log.New(structuredWriter{logger: logger}, ``, 0)
+}
+
+// logFailure logs err, a failure of module in phase. With a structured logger it is logged
+// at error level with the module and phase as attributes, leaving out the empty ones.
+func (c *container) logFailure(module string, phase Phase, err error) {
+	w, ok := c.logger.Writer().(structuredWriter)
+	if !ok {
+		c.logger.Println(err)
+		return
+	}
+
+	var attrs []any
+	if module != `` {
+		attrs = append(attrs, moduleLogAttr, module)
+	}
+	if phase != `` {
+		attrs = append(attrs, phaseLogAttr, string(phase))
+	}
+	w.logger.Error(err.Error(), attrs...)
+}
+
+// logWarning logs a warning about module, or about the container when module is empty.
+// With a structured logger it is logged at warning level with the module as an attribute,
+// otherwise as a line prefixed with "warning: ".
+func (c *container) logWarning(module string, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+
+	w, ok := c.logger.Writer().(structuredWriter)
+	if !ok {
+		c.logger.Print(`warning: ` + msg)
+		return
+	}
+
+	if module == `` {
+		w.logger.Warn(msg)
+		return
+	}
+	w.logger.Warn(msg, moduleLogAttr, module)
+}
+
+// StructuredLogger returns a logger with levels and attributes that writes where Logger
+// does. With WithLogger the records go to the configured logger as they are, attributes
+// included; otherwise each record is written as a line, with its attributes appended as
+// key=value pairs and warnings prefixed with "warning: ". Quiet and SetLogOutput apply to it.
+func (c *container) StructuredLogger() StructuredLogger {
+	return containerLogger{c: c}
+}
+
+// containerLogger is the StructuredLogger returned by StructuredLogger.
+type containerLogger struct {
+	c *container
+}
+
+func (l containerLogger) Info(msg string, args ...any) {
+	if w, ok := l.c.logger.Writer().(structuredWriter); ok {
+		w.logger.Info(msg, args...)
+		return
+	}
+	l.c.logger.Print(formatRecord(msg, args))
+}
+
+func (l containerLogger) Warn(msg string, args ...any) {
+	if w, ok := l.c.logger.Writer().(structuredWriter); ok {
+		w.logger.Warn(msg, args...)
+		return
+	}
+	l.c.logger.Print(`warning: ` + formatRecord(msg, args))
+}
+
+func (l containerLogger) Error(msg string, args ...any) {
+	if w, ok := l.c.logger.Writer().(structuredWriter); ok {
+		w.logger.Error(msg, args...)
+		return
+	}
+	l.c.logger.Print(formatRecord(msg, args))
+}
+
+// formatRecord formats msg with args, alternating keys and values, as a single log line.
+func formatRecord(msg string, args []any) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			fmt.Fprintf(&b, ` %v`, args[i])
+			break
+		}
+		fmt.Fprintf(&b, ` %v=%v`, args[i], args[i+1])
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ModuleGroup is a named handle over bound modules that are always operated on together,
+// such as the modules of one subsystem. It is not a binding itself.
+type ModuleGroup struct {
+	Name    string
+	Members []string
+}
+
+// BindGroup registers a module group named name over the given members, replacing any
+// group registered under that name.
+func (c *container) BindGroup(name string, members ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.moduleGroups[name] = ModuleGroup{Name: name, Members: append([]string{}, members...)}
+}
+
+// Group returns the module group registered under name.
+func (c *container) Group(name string) (ModuleGroup, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	g, ok := c.moduleGroups[name]
+	g.Members = append([]string{}, g.Members...)
+	return g, ok
+}
+
+// InitGroup initializes the members of the named group in dependency order, like InitE.
+func (c *container) InitGroup(name string) error {
+	g, err := c.group(name)
+	if err != nil {
+		return err
+	}
+	return c.InitE(g.Members...)
+}
+
+// StartGroup starts the members of the named group in dependency order.
+//
+// Unlike Start it doesn't block: it returns once the members are launched.
+func (c *container) StartGroup(name string) error {
+	g, err := c.group(name)
+	if err != nil {
+		return err
+	}
+	return c.launch(g.Members)
+}
+
+// ShutdownGroup stops the members of the named group in reverse dependency order, like
+// ShutdownE, but leaves the rest of the container running.
+func (c *container) ShutdownGroup(name string) error {
+	g, err := c.group(name)
+	if err != nil {
+		return err
+	}
+
+	order, err := c.sortModules(g.Members)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := len(order) - 1; i >= 0; i-- {
+		module := order[i]
+		if !isStoppable(c.binding(module)) || c.isDisabled(module) {
+			continue
+		}
+		if state := c.State(module); state != StateRunning && state != StateInitialized {
+			continue
+		}
+		if err := c.StopModule(module); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// group returns the named module group or an error if there is none.
+func (c *container) group(name string) (ModuleGroup, error) {
+	g, ok := c.Group(name)
+	if !ok {
+		return ModuleGroup{}, fmt.Errorf(`container: group [%s] not found`, name)
+	}
+	return g, nil
+}
@@ -0,0 +1,89 @@
+package container
+
+// setInitializing records the module whose Init is running, so resolves can be attributed to it.
+func (c *container) setInitializing(name string) {
+	if !c.observeDeps {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.initializing = name
+}
+
+// observeResolve records name as a dependency of the module whose Init is running, if any.
+func (c *container) observeResolve(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	module := c.initializing
+	if module == `` || module == name || c.isSelf(name) {
+		return
+	}
+
+	if c.observed == nil {
+		c.observed = make(map[string]map[string]bool)
+	}
+	if c.observed[module] == nil {
+		c.observed[module] = make(map[string]bool)
+	}
+	c.observed[module][name] = true
+}
+
+// observedOrder reorders order, a shutdown order, so every module comes before the modules
+// it resolved during Init or declares as dependencies. Ties keep their place in order, and
+// order is returned as is when the dependencies form a cycle. The caller must hold the lock.
+func (c *container) observedOrder(order []string) []string {
+	// dependents[m] are the modules that must stop before m
+	dependents := make(map[string]map[string]bool, len(order))
+	pending := make(map[string]bool, len(order))
+	for _, name := range order {
+		pending[name] = true
+	}
+	for _, name := range order {
+		obj, _ := c.store.Get(name)
+		deps := c.keys(append(append([]string{}, c.bindOpts[name].dependsOn...), dependencies(constructed(obj))...))
+		for dep := range c.observed[name] {
+			deps = append(deps, dep)
+		}
+		for _, dep := range deps {
+			if dep == name || !pending[dep] {
+				continue
+			}
+			if dependents[dep] == nil {
+				dependents[dep] = make(map[string]bool)
+			}
+			dependents[dep][name] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(order))
+	for len(sorted) < len(order) {
+		progressed := false
+		for _, name := range order {
+			if !pending[name] || !stoppedFirst(dependents[name], pending) {
+				continue
+			}
+			pending[name] = false
+			sorted = append(sorted, name)
+			progressed = true
+			break
+		}
+		if !progressed {
+			c.logWarning(``, `observed dependencies form a cycle, using the default shutdown order`)
+			return order
+		}
+	}
+	return sorted
+}
+
+// stoppedFirst reports whether none of the dependents are still pending.
+func stoppedFirst(dependents, pending map[string]bool) bool {
+	for dependent := range dependents {
+		if pending[dependent] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,238 @@
+package container
+
+import (
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures a container created by NewContainer.
+type Option func(*container)
+
+// WithLogger sends the container logs, and anything modules log through Logger, to a
+// structured logger such as a *slog.Logger, one record per line. Failures are logged at
+// error level with the module and phase as attributes.
+func WithLogger(logger StructuredLogger) Option {
+	return func(c *container) {
+		c.logger = newStructuredLogger(logger)
+	}
+}
+
+// WithStopTimeout sets how long Shutdown waits for a module's Stop to return.
+//
+// A module that does not stop in time is logged and abandoned, so one hung module
+// cannot block the rest of the shutdown. Zero, the default, waits indefinitely.
+func WithStopTimeout(d time.Duration) Option {
+	return func(c *container) {
+		c.stopTimeout = d
+	}
+}
+
+// WithStopGracePeriod sets how long Shutdown keeps waiting for a module that exceeded
+// its stop timeout before calling ForceStop on it and moving on.
+func WithStopGracePeriod(d time.Duration) Option {
+	return func(c *container) {
+		c.stopGracePeriod = d
+	}
+}
+
+// WithUnresolvedReport makes Shutdown log every binding that was never resolved.
+//
+// It helps to find modules that are bound but unused.
+func WithUnresolvedReport() Option {
+	return func(c *container) {
+		c.reportUnresolved = true
+	}
+}
+
+// WithRecover makes the container recover panics raised by modules during Init, Run and Stop.
+//
+// A recovered panic is turned into a *LifecyclePanic carrying the module name, phase and
+// stack, and is returned from InitE, StartE or ShutdownE. Without it, panics propagate to
+// fail fast, raised again as a *LifecyclePanic.
+func WithRecover() Option {
+	return func(c *container) {
+		c.recoverPanics = true
+	}
+}
+
+// WithShutdownOrder makes ShutdownAll stop the given modules first, in the given order.
+//
+// Started modules that are not listed are stopped afterwards in reverse start order.
+func WithShutdownOrder(modules ...string) Option {
+	return func(c *container) {
+		c.stopOrder = modules
+	}
+}
+
+// WithStrict enables extra wiring checks that log likely mistakes, such as a stoppable
+// module that was never started.
+func WithStrict() Option {
+	return func(c *container) {
+		c.strict = true
+	}
+}
+
+// WithClock sets the clock used by all timeout logic. It defaults to the system clock.
+func WithClock(clock Clock) Option {
+	return func(c *container) {
+		c.clock = clock
+	}
+}
+
+// WithStrictResolve makes Resolve and TryResolve refuse with ErrShuttingDown once shutdown
+// has begun, which catches modules grabbing dependencies that may already be stopped.
+func WithStrictResolve() Option {
+	return func(c *container) {
+		c.strictResolve = true
+	}
+}
+
+// WithErrorHandler makes Resolve, GetGlobalConfig, Init and Start hand their failures to
+// handler instead of panicking, so a misconfigured module is reported rather than crashing
+// the process. Resolve and GetGlobalConfig then return nil, and ResolveType and Config the
+// zero value.
+func WithErrorHandler(handler func(error)) Option {
+	return func(c *container) {
+		c.errorHandler = handler
+	}
+}
+
+// WithAutoInject makes Init populate each module's `inject` tagged fields, using Inject,
+// before calling the module's Init method.
+func WithAutoInject() Option {
+	return func(c *container) {
+		c.autoInject = true
+	}
+}
+
+// WithEarlyExitWindow sets how soon after starting a module's Run may return before the
+// container warns that it probably failed to start. It defaults to 100ms; zero disables the check.
+//
+// Modules implementing OneShot are exempt.
+func WithEarlyExitWindow(d time.Duration) Option {
+	return func(c *container) {
+		c.earlyExitWindow = d
+	}
+}
+
+// WithEarlyExitFailure treats a Run returning within the early exit window as a failure,
+// which marks the module failed and stops the container.
+func WithEarlyExitFailure() Option {
+	return func(c *container) {
+		c.earlyExitFails = true
+	}
+}
+
+// WithStore replaces the in-memory map holding the bindings with a custom Store, for example
+// one backed by a plugin registry.
+func WithStore(store Store) Option {
+	return func(c *container) {
+		c.store = store
+	}
+}
+
+// WithSignalShutdown makes Start listen for the given signals, SIGINT and SIGTERM if none
+// are given, and shut down every module with ShutdownAll when one arrives.
+func WithSignalShutdown(sigs ...os.Signal) Option {
+	return func(c *container) {
+		c.signalShutdown = true
+		c.signals = sigs
+	}
+}
+
+// WithSignalSource is like WithSignalShutdown but takes the signals from source instead of
+// the OS, so the signal-driven shutdown can be tested with a SignalChan.
+func WithSignalSource(source SignalSource) Option {
+	return func(c *container) {
+		c.signalShutdown = true
+		c.signalSource = source
+	}
+}
+
+// WithHealthTimeout bounds how long Health, Liveness and Readiness wait for the module checks.
+// A check that doesn't return in time is reported as timed out. Zero, the default, only
+// honors the deadline of the context passed in.
+func WithHealthTimeout(d time.Duration) Option {
+	return func(c *container) {
+		c.healthTimeout = d
+	}
+}
+
+// WithHealthParallelism sets how many module checks Health, Liveness and Readiness run at
+// once. It defaults to runtime.NumCPU().
+func WithHealthParallelism(n int) Option {
+	return func(c *container) {
+		c.healthParallelism = n
+	}
+}
+
+// WithTracer makes the container emit OpenTelemetry spans for Init, with a child span per
+// module, for Start and for ShutdownAll, with a child span per module stopped. The Init and
+// Start spans of a boot share a container.bootstrap parent span. Spans carry the module
+// name, and failures set the span status. Without a tracer, tracing is a no-op.
+func WithTracer(tracer trace.Tracer) Option {
+	return func(c *container) {
+		c.tracer = tracer
+	}
+}
+
+// WithKeyNormalizer normalizes every binding key with fn, such as strings.ToLower, both when
+// binding and when resolving or naming modules in lifecycle calls, so "DB" and "db" refer to
+// the same binding. fn must be idempotent, so normalizing a key twice gives the same key;
+// binding panics otherwise. The default leaves keys as they are.
+func WithKeyNormalizer(fn func(string) string) Option {
+	return func(c *container) {
+		c.keyNormalizer = fn
+	}
+}
+
+// WithObservedDependencies records which modules each module resolves during its Init and
+// uses those observed dependencies, together with the declared ones, to order ShutdownAll:
+// a module is stopped before the modules it resolved. When the combined graph has a cycle,
+// shutdown falls back to the default order. An order set with WithShutdownOrder takes precedence.
+func WithObservedDependencies() Option {
+	return func(c *container) {
+		c.observeDeps = true
+	}
+}
+
+// WithReadinessInterval sets how often the readiness gate behind Ready polls the readiness
+// checks once the container is started. The default is one second.
+func WithReadinessInterval(d time.Duration) Option {
+	return func(c *container) {
+		c.readinessInterval = d
+	}
+}
+
+// WithConfigWatch makes Start watch for config changes, checking every interval whether the
+// environment or any of files changed, and reload every global config with ReloadConfig
+// when one did. A failed reload is logged and leaves the current configs in place.
+func WithConfigWatch(interval time.Duration, files ...string) Option {
+	return func(c *container) {
+		c.configWatchInterval = interval
+		c.configWatchFiles = files
+	}
+}
+
+// WithBindTimeout bounds how long a factory binding may take to construct its object, whether
+// it is constructed by a resolve or by the lifecycle. A factory that doesn't return in time
+// fails the resolve with ErrBindTimeout naming the binding, keeps running in the background,
+// and its result is kept for later resolves. Zero, the default, waits indefinitely.
+func WithBindTimeout(d time.Duration) Option {
+	return func(c *container) {
+		c.bindTimeout = d
+	}
+}
+
+// WithStartupDeadline bounds the whole startup, from the first Init or Start until every
+// module is running and every started ReadinessChecker reports ready. When it is not up in
+// time, the pending modules are logged, every module is shut down and StartE returns
+// ErrStartupTimeout. An Init still running at the deadline has its context cancelled, so an
+// InitableCtx module aborts, and InitE returns ErrStartupTimeout too.
+func WithStartupDeadline(d time.Duration) Option {
+	return func(c *container) {
+		c.startupDeadline = d
+	}
+}
@@ -0,0 +1,174 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Dependent interface is used by modules that depend on other modules.
+//
+// Modules returned by DependsOn are initialized and started before the module itself,
+// and must be bound.
+type Dependent interface {
+	DependsOn() []string
+}
+
+// SoftDependent interface is used by modules with optional collaborators.
+//
+// Modules returned by SoftDependsOn are initialized and started before the module itself
+// when they are bound, and are ignored when they are not.
+type SoftDependent interface {
+	SoftDependsOn() []string
+}
+
+// dependencies returns the hard and soft dependencies of obj.
+func dependencies(obj any) []string {
+	var deps []string
+	if d, ok := obj.(Dependent); ok {
+		deps = append(deps, d.DependsOn()...)
+	}
+	if d, ok := obj.(SoftDependent); ok {
+		deps = append(deps, d.SoftDependsOn()...)
+	}
+	return deps
+}
+
+// sortModules orders modules so that every module comes after the modules it depends on.
+//
+// Ties are broken by the order the modules were provided in, which keeps the result
+// deterministic and leaves a list without declared dependencies untouched.
+// Dependencies that are not part of modules do not affect the order, but every hard
+// dependency must be bound.
+func (c *container) sortModules(modules []string) ([]string, error) {
+	modules = c.keys(modules)
+	if err := c.checkDependencies(modules); err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]bool, len(modules))
+	for _, name := range modules {
+		pending[name] = true
+	}
+
+	sorted := make([]string, 0, len(modules))
+	for len(sorted) < len(pending) {
+		progressed := false
+		for _, name := range modules {
+			if !pending[name] || !c.depsSatisfied(name, pending) {
+				continue
+			}
+			pending[name] = false
+			sorted = append(sorted, name)
+			progressed = true
+			break
+		}
+
+		if !progressed {
+			cycle := make([]string, 0)
+			for _, name := range modules {
+				if pending[name] {
+					cycle = append(cycle, name)
+				}
+			}
+			return nil, fmt.Errorf(`container: dependency cycle between modules [%s]`, strings.Join(cycle, `, `))
+		}
+	}
+
+	return sorted, nil
+}
+
+// hardDependencies returns the dependencies of the module bound under name that must be
+// bound: those from its DependsOn and those declared with the DependsOn bind option. The
+// names are normalized like binding keys.
+func (c *container) hardDependencies(name string) []string {
+	deps := append([]string{}, c.options(name).dependsOn...)
+	if d, ok := c.binding(name).(Dependent); ok {
+		deps = append(deps, d.DependsOn()...)
+	}
+	return c.keys(deps)
+}
+
+// dependenciesOf returns the hard and soft dependencies of the module bound under name,
+// normalized like binding keys.
+func (c *container) dependenciesOf(name string) []string {
+	return c.keys(append(append([]string{}, c.options(name).dependsOn...), dependencies(c.binding(name))...))
+}
+
+// checkDependencies returns an error naming the hard dependencies of modules that are not bound.
+func (c *container) checkDependencies(modules []string) error {
+	missing := make([]string, 0)
+	for _, name := range modules {
+		for _, dep := range c.hardDependencies(name) {
+			if !c.isBound(dep) {
+				missing = append(missing, fmt.Sprintf(`%s -> %s`, name, dep))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf(`container: missing dependencies [%s]`, strings.Join(missing, `, `))
+	}
+	return nil
+}
+
+// depsSatisfied reports whether none of the dependencies of the module are still pending.
+func (c *container) depsSatisfied(name string, pending map[string]bool) bool {
+	for _, dep := range c.dependenciesOf(name) {
+		if dep != name && pending[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// StartOrder returns the order Start would start the given modules in, without starting
+// them. Disabled modules are left out, and a dependency cycle is returned as an error.
+func (c *container) StartOrder(modules ...string) ([]string, error) {
+	sorted, err := c.sortModules(modules)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]string, 0, len(sorted))
+	for _, name := range sorted {
+		if !c.isDisabled(name) {
+			order = append(order, name)
+		}
+	}
+	return order, nil
+}
+
+// Dependents returns the modules that depend on name directly or transitively, through
+// their DependsOn or SoftDependsOn, sorted by name. It shows what stopping or rebinding a
+// module would affect. Factory bindings are only considered once they are constructed.
+func (c *container) Dependents(name string) []string {
+	return c.dependentsOf(c.key(name))
+}
+
+// dependentsOf returns the modules that depend on name, directly or transitively, sorted by name.
+func (c *container) dependentsOf(name string) []string {
+	reverse := make(map[string][]string)
+	for _, b := range c.walkBindings() {
+		for _, dep := range c.dependenciesOf(b.Name) {
+			reverse[dep] = append(reverse[dep], b.Name)
+		}
+	}
+
+	seen := map[string]bool{name: true}
+	queue := []string{name}
+	dependents := make([]string, 0)
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, dependent := range reverse[next] {
+			if !seen[dependent] {
+				seen[dependent] = true
+				dependents = append(dependents, dependent)
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	sort.Strings(dependents)
+	return dependents
+}
@@ -0,0 +1,54 @@
+package container
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type orderModule struct{}
+
+func (orderModule) Init(Container) error { return nil }
+
+func TestStartOrderIsDeterministic(t *testing.T) {
+	wire := func() []string {
+		c := NewContainer()
+		c.BindWithOptions(`api`, orderModule{}, DependsOn(`db`, `cache`))
+		c.BindWithOptions(`worker`, orderModule{}, DependsOn(`queue`, `db`))
+		for _, name := range []string{`queue`, `db`, `cache`} {
+			c.Bind(name, orderModule{})
+		}
+
+		order, err := c.StartOrder(`worker`, `api`, `queue`, `db`, `cache`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return order
+	}
+
+	// ties are broken by the order the modules were given in
+	want := []string{`queue`, `db`, `worker`, `cache`, `api`}
+	for range 10 {
+		if order := wire(); !slices.Equal(order, want) {
+			t.Fatalf(`expected order %v, got %v`, want, order)
+		}
+	}
+}
+
+func TestStartOrderNormalizesDependencies(t *testing.T) {
+	c := NewContainer(WithKeyNormalizer(strings.ToLower))
+	c.BindWithOptions(`api`, orderModule{}, DependsOn(`DB`))
+	c.Bind(`db`, orderModule{})
+
+	order, err := c.StartOrder(`api`, `db`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{`db`, `api`}; !slices.Equal(order, want) {
+		t.Fatalf(`expected order %v, got %v`, want, order)
+	}
+
+	if dependents := c.Dependents(`DB`); !slices.Equal(dependents, []string{`api`}) {
+		t.Fatalf(`expected api to depend on db, got %v`, dependents)
+	}
+}
@@ -0,0 +1,108 @@
+package container
+
+import "sync"
+
+// BindingSnapshot is a copy of the bindings of a container, with their bind options and
+// lifecycle states, taken by Snapshot and put back by Restore.
+type BindingSnapshot struct {
+	bindings map[string]any
+	opts     map[string]bindOptions
+	states   map[string]ModuleState
+}
+
+// Override replaces the object bound under name until the returned restore function is
+// called, which puts the previous binding and its state back, or removes name if nothing
+// was bound. The bind options of name are kept. It lets a test swap a real client for a
+// fake without rebuilding the wiring:
+//
+//	restore := c.Override("kafka", &fakeProducer{})
+//	defer restore()
+//
+// Like Bind, it panics with ErrContainerFrozen once the container is frozen.
+func (c *container) Override(name string, obj any) (restore func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := c.key(name)
+	prev, bound := c.store.Get(key)
+	state, hasState := c.states[key]
+	c.bind(key, obj)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.lock.Lock()
+			defer c.lock.Unlock()
+
+			if bound {
+				c.store.Set(key, prev)
+			} else {
+				c.store.Delete(key)
+			}
+			if hasState {
+				c.states[key] = state
+			} else {
+				delete(c.states, key)
+			}
+		})
+	}
+}
+
+// Snapshot copies the current bindings, their bind options and lifecycle states, so a test
+// can change the wiring and Restore it afterwards. Bound objects are not copied themselves.
+func (c *container) Snapshot() BindingSnapshot {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	keys := c.store.Keys()
+	s := BindingSnapshot{
+		bindings: make(map[string]any, len(keys)),
+		opts:     make(map[string]bindOptions),
+		states:   make(map[string]ModuleState, len(keys)),
+	}
+	for _, name := range keys {
+		s.bindings[name], _ = c.store.Get(name)
+		if o, ok := c.bindOpts[name]; ok {
+			s.opts[name] = o
+		}
+		if state, ok := c.states[name]; ok {
+			s.states[name] = state
+		}
+	}
+	return s
+}
+
+// Restore puts back the bindings, bind options and states of a snapshot taken with
+// Snapshot. Names bound since the snapshot was taken are removed.
+//
+// Like Bind, it panics with ErrContainerFrozen once the container is frozen.
+func (c *container) Restore(s BindingSnapshot) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, name := range c.store.Keys() {
+		if _, ok := s.bindings[name]; ok {
+			continue
+		}
+		c.checkFrozen(name)
+		c.store.Delete(name)
+		delete(c.bindOpts, name)
+		delete(c.states, name)
+	}
+
+	for name, obj := range s.bindings {
+		c.checkFrozen(name)
+		c.store.Set(name, obj)
+
+		if o, ok := s.opts[name]; ok {
+			c.bindOpts[name] = o
+		} else {
+			delete(c.bindOpts, name)
+		}
+		if state, ok := s.states[name]; ok {
+			c.states[name] = state
+		} else {
+			delete(c.states, name)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package container
+
+import "sort"
+
+// Plugin interface is used by bindings that DiscoverPlugins can find. OnDiscover is the
+// registration hook, called each time the plugin is discovered.
+type Plugin interface {
+	OnDiscover(c Container)
+}
+
+// Ordered interface is used by plugins that care where DiscoverPlugins places them. Lower
+// orders come first, and plugins without an order count as zero.
+type Ordered interface {
+	Order() int
+}
+
+// DiscoverPlugins returns every binding implementing T, sorted by Order and then by name,
+// after calling OnDiscover on each of them in that order.
+//
+// Factory bindings are only considered once they are constructed, and the bindings of the
+// container itself are skipped.
+func DiscoverPlugins[T Plugin](c Container) []T {
+	w, ok := c.(bindingWalker)
+	if !ok {
+		return nil
+	}
+
+	plugins := make([]T, 0)
+	for _, b := range w.walkBindings() {
+		if p, ok := b.Obj.(T); ok {
+			plugins = append(plugins, p)
+		}
+	}
+
+	// walkBindings is sorted by name, so a stable sort keeps name order among equal orders
+	sort.SliceStable(plugins, func(i, j int) bool {
+		return order(plugins[i]) < order(plugins[j])
+	})
+
+	for _, p := range plugins {
+		p.OnDiscover(c)
+	}
+	return plugins
+}
+
+// order returns the order of an Ordered plugin, or zero.
+func order(p any) int {
+	if o, ok := p.(Ordered); ok {
+		return o.Order()
+	}
+	return 0
+}
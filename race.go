@@ -0,0 +1,95 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+)
+
+// race tracks the modules started by StartRace.
+type race struct {
+	members map[string]bool
+	done    chan raceResult
+}
+
+// raceResult is the outcome of the Run of a racing module.
+type raceResult struct {
+	module string
+	err    error
+}
+
+// StartRace starts the given runnable modules and waits for the first Run to return. The
+// other modules are then stopped, and the one that finished first is returned as the
+// winner, together with its Run error and the errors stopping the others.
+//
+// Modules that aren't Stoppable cannot be stopped and keep running. If the container is
+// stopped before any module finishes, ErrShuttingDown is returned.
+func (c *container) StartRace(modules ...string) (string, error) {
+	modules = c.keys(modules)
+
+	r := &race{members: make(map[string]bool, len(modules)), done: make(chan raceResult, len(modules))}
+	for _, module := range modules {
+		r.members[module] = true
+	}
+
+	c.lock.Lock()
+	c.race = r
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		c.race = nil
+		c.lock.Unlock()
+	}()
+
+	if err := c.launch(modules); err != nil {
+		return ``, err
+	}
+
+	var winner raceResult
+	select {
+	case winner = <-r.done:
+	case <-c.stopped:
+		select {
+		case winner = <-r.done:
+		default:
+			return ``, fmt.Errorf(`%w, race abandoned`, ErrShuttingDown)
+		}
+	}
+
+	c.logger.Printf(`module %s finished first, stopping the others`, winner.module)
+
+	errs := []error{winner.err}
+	for i := len(modules) - 1; i >= 0; i-- {
+		module := modules[i]
+		if module == winner.module || !c.IsRunning(module) {
+			continue
+		}
+		if !isStoppable(c.binding(module)) {
+			c.logWarning(module, `module %s is not stoppable, leaving it running`, module)
+			continue
+		}
+		if err := c.StopModule(module); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return winner.module, errors.Join(errs...)
+}
+
+// finishRace reports the outcome of a Run to StartRace if the module is racing, and
+// reports whether it is.
+func (c *container) finishRace(module string, err error) bool {
+	c.lock.RLock()
+	r := c.race
+	c.lock.RUnlock()
+
+	if r == nil || !r.members[module] {
+		return false
+	}
+
+	select {
+	case r.done <- raceResult{module: module, err: err}:
+	default:
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package container
+
+import (
+	"context"
+	"time"
+)
+
+// defaultReadinessInterval is how often the readiness gate polls when WithReadinessInterval isn't used.
+const defaultReadinessInterval = time.Second
+
+// Ready reports whether the container is ready for traffic: it has been started, shutdown
+// hasn't begun, and every running module implementing ReadinessChecker passed its last check.
+//
+// It reads the result of the readiness gate, which polls the checks in the background, so
+// it is cheap enough to call from a load balancer probe.
+func (c *container) Ready() bool {
+	return c.ready.Load()
+}
+
+// ReadinessChanged returns a channel receiving the new readiness whenever Ready flips. Only
+// the latest change is kept for a receiver that falls behind.
+func (c *container) ReadinessChanged() <-chan bool {
+	return c.readyChanged
+}
+
+// startWatchers starts the readiness gate of a started container, and the config watch if
+// one is set. They are tied to the current container context, so they must be started
+// again once that context is replaced, as RestartAll does.
+func (c *container) startWatchers() {
+	ctx := c.Context()
+	go c.watchReadiness(ctx)
+	if c.configWatchInterval > 0 {
+		go c.watchConfig(ctx)
+	}
+}
+
+// watchReadiness polls readiness until ctx, the container context, is cancelled, and marks
+// the container not ready as soon as it is.
+func (c *container) watchReadiness(ctx context.Context) {
+	interval := c.readinessInterval
+	if interval <= 0 {
+		interval = defaultReadinessInterval
+	}
+
+	for {
+		c.setReady(c.readyNow(ctx))
+
+		select {
+		case <-c.clock.After(interval):
+		case <-ctx.Done():
+			c.setReady(false)
+			return
+		}
+	}
+}
+
+// readyNow runs the readiness checks of the running modules and reports whether all passed.
+func (c *container) readyNow(ctx context.Context) bool {
+	if c.stopRequested() {
+		return false
+	}
+
+	for name, err := range c.Readiness(ctx) {
+		if err != nil && c.IsRunning(name) {
+			return false
+		}
+	}
+	return ctx.Err() == nil
+}
+
+// setReady records the readiness and publishes it on ReadinessChanged if it flipped.
+func (c *container) setReady(ready bool) {
+	if c.ready.Swap(ready) == ready {
+		return
+	}
+
+	// keep only the latest change for a receiver that fell behind
+	select {
+	case <-c.readyChanged:
+	default:
+	}
+	select {
+	case c.readyChanged <- ready:
+	default:
+	}
+}
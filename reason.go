@@ -0,0 +1,119 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// ShutdownCause tells what initiated a shutdown.
+type ShutdownCause int
+
+const (
+	// CauseNone means no shutdown has been initiated.
+	CauseNone ShutdownCause = iota
+	// CauseExplicit means Shutdown, ShutdownAll or Close was called.
+	CauseExplicit
+	// CauseSignal means a stop signal, such as an OS signal, was received.
+	CauseSignal
+	// CauseModule means a module requested the shutdown or failed.
+	CauseModule
+	// CauseContext means a context the container was tied to was cancelled.
+	CauseContext
+	// CauseTimeout means a deadline set on the container passed, such as the startup deadline.
+	CauseTimeout
+)
+
+func (c ShutdownCause) String() string {
+	switch c {
+	case CauseNone:
+		return `none`
+	case CauseExplicit:
+		return `explicit`
+	case CauseSignal:
+		return `signal`
+	case CauseModule:
+		return `module`
+	case CauseContext:
+		return `context`
+	case CauseTimeout:
+		return `timeout`
+	default:
+		return fmt.Sprintf(`ShutdownCause(%d)`, int(c))
+	}
+}
+
+// ShutdownReason describes what initiated a shutdown, such as "SIGTERM" or
+// "module payments requested".
+type ShutdownReason struct {
+	Cause  ShutdownCause
+	Detail string
+}
+
+func (r ShutdownReason) String() string {
+	if r.Detail == `` {
+		return r.Cause.String()
+	}
+	return r.Detail
+}
+
+// ShutdownNotifiable interface is used by stoppable modules that want to know why they are
+// being stopped. OnShutdown is called right before Stop.
+type ShutdownNotifiable interface {
+	OnShutdown(reason ShutdownReason)
+}
+
+// ShutdownReason returns what initiated the shutdown, or a reason with CauseNone if
+// shutdown has not begun.
+func (c *container) ShutdownReason() ShutdownReason {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.reason
+}
+
+// beginShutdown marks shutdown as begun, cancels the container context and records reason,
+// unless an earlier reason was already recorded. Only the first reason is kept and logged.
+func (c *container) beginShutdown(reason ShutdownReason) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.shuttingDown.Store(true)
+	c.cancel()
+	if c.reason.Cause != CauseNone {
+		return
+	}
+	c.reason = reason
+	c.logger.Printf(`shutting down: %s`, reason)
+}
+
+// signalReason returns the shutdown reason for a value received from a stop signal channel.
+func signalReason(sig any) ShutdownReason {
+	switch s := sig.(type) {
+	case os.Signal:
+		return ShutdownReason{Cause: CauseSignal, Detail: signalName(s)}
+	case fmt.Stringer:
+		return ShutdownReason{Cause: CauseSignal, Detail: s.String()}
+	default:
+		return ShutdownReason{Cause: CauseSignal, Detail: `stop signal`}
+	}
+}
+
+// signalName returns the conventional name of the common shutdown signals, such as SIGTERM.
+func signalName(sig os.Signal) string {
+	switch sig {
+	case os.Interrupt:
+		return `SIGINT`
+	case syscall.SIGTERM:
+		return `SIGTERM`
+	case syscall.SIGHUP:
+		return `SIGHUP`
+	case syscall.SIGQUIT:
+		return `SIGQUIT`
+	default:
+		return sig.String()
+	}
+}
+
+// explicitReason is the shutdown reason recorded when shutdown is called directly.
+var explicitReason = ShutdownReason{Cause: CauseExplicit, Detail: `shutdown called`}
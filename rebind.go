@@ -0,0 +1,50 @@
+package container
+
+import "fmt"
+
+// Rebind replaces the object bound under name and restarts the running modules that depend
+// on it, directly or transitively, so they pick up the new object.
+//
+// The affected modules are stopped in reverse dependency order, then initialized and
+// started again in dependency order. If the module bound under name was running itself,
+// the new object is restarted along with them. Modules that don't depend on name are
+// left untouched.
+func (c *container) Rebind(name string, obj any) error {
+	name = c.key(name)
+
+	c.lock.Lock()
+	frozen := c.frozen
+	c.lock.Unlock()
+	if frozen {
+		return fmt.Errorf(`%w, rebinding [%s] refused`, ErrContainerFrozen, name)
+	}
+
+	affected := make([]string, 0)
+	for _, module := range append([]string{name}, c.dependentsOf(name)...) {
+		if c.IsRunning(module) {
+			affected = append(affected, module)
+		}
+	}
+
+	order, err := c.sortModules(affected)
+	if err != nil {
+		return err
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		module := order[i]
+		c.logger.Printf(`module %s stopping for rebind of %s...`, module, name)
+		if err := c.call(module, PhaseStop, func() error { return c.stop(module) }); err != nil {
+			return fmt.Errorf(`container: rebinding [%s] failed: %w`, name, err)
+		}
+	}
+
+	c.Bind(name, obj)
+
+	for _, module := range order {
+		if err := c.initModule(c.Context(), module); err != nil {
+			return fmt.Errorf(`container: rebinding [%s] failed: %w`, name, err)
+		}
+	}
+	return c.launch(order)
+}
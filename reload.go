@@ -0,0 +1,197 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"reflect"
+	"sort"
+
+	gocon "github.com/wgarunap/goconf"
+)
+
+// ConfigReloadable interface is used by modules that apply config changes at runtime.
+// OnConfigReload is called by ReloadConfig with the new config of every key that changed.
+type ConfigReloadable interface {
+	OnConfigReload(key string, cfg any) error
+}
+
+// ConfigDependent interface is used by modules that name the global config keys they use.
+// Init fails for the module while any of those keys is not set, and a ConfigReloadable
+// module implementing it is notified about changes to those keys instead of its own.
+type ConfigDependent interface {
+	RequiresConfig() []string
+}
+
+// ReloadConfig loads the global configs registered under keys again, or all of them if no
+// key is given, and swaps in the ones whose value changed.
+//
+// Every config is loaded and validated before any is swapped in, so a failure leaves all
+// the current configs intact. Changed configs are compared with the previous value using
+// reflect.DeepEqual, and only ConfigReloadable modules depending on a changed key are
+// notified. A reload that changes nothing notifies nobody.
+func (c *container) ReloadConfig(keys ...string) error {
+	changed, err := c.loadChangedConfigs(keys)
+	if err != nil || len(changed) == 0 {
+		return err
+	}
+	return c.swapConfigs(changed)
+}
+
+// loadChangedConfigs loads and validates the global configs registered under keys, or all
+// of them, and returns the ones whose value changed, without swapping anything in.
+//
+// Configs that aren't pointers can't be loaded again and keep their value.
+func (c *container) loadChangedConfigs(keys []string) (map[string]any, error) {
+	c.lock.Lock()
+	if len(keys) == 0 {
+		for key := range c.moduleConfigs {
+			keys = append(keys, key)
+		}
+	}
+	previous := make(map[string]any, len(keys))
+	for _, key := range keys {
+		cfg, ok := c.moduleConfigs[key]
+		if !ok {
+			c.lock.Unlock()
+			return nil, fmt.Errorf(`container: config [%s] not found, reloading failed`, key)
+		}
+		previous[key] = cfg
+	}
+	c.lock.Unlock()
+
+	changed := make(map[string]any)
+	for _, key := range keys {
+		if t := reflect.TypeOf(previous[key]); t == nil || t.Kind() != reflect.Pointer {
+			continue
+		}
+		cfg, err := loadFresh(previous[key])
+		if err != nil {
+			return nil, fmt.Errorf(`container: config [%s] reloading failed: %w`, key, err)
+		}
+		if !reflect.DeepEqual(previous[key], cfg) {
+			changed[key] = cfg
+		}
+	}
+	return changed, nil
+}
+
+// swapConfigs swaps in the changed configs and notifies the modules affected by them.
+func (c *container) swapConfigs(changed map[string]any) error {
+	c.lock.Lock()
+	for key, cfg := range changed {
+		c.moduleConfigs[key] = cfg
+	}
+	c.lock.Unlock()
+
+	return c.notifyReload(changed)
+}
+
+// loadFresh loads a new config of the same type as cfg, which must be a pointer.
+func loadFresh(cfg any) (any, error) {
+	t := reflect.TypeOf(cfg)
+	if t == nil || t.Kind() != reflect.Pointer {
+		return nil, fmt.Errorf(`%T is not a pointer`, cfg)
+	}
+
+	fresh, ok := reflect.New(t.Elem()).Interface().(gocon.Configer)
+	if !ok {
+		return nil, fmt.Errorf(`%T does not implement goconf.Configer`, cfg)
+	}
+	if err := gocon.Load(fresh); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
+
+// notifyReload calls OnConfigReload on every enabled module affected by the changed configs.
+func (c *container) notifyReload(changed map[string]any) error {
+	var errs []error
+	for _, b := range c.walkBindings() {
+		r, ok := b.Obj.(ConfigReloadable)
+		if !ok || c.isDisabled(b.Name) {
+			continue
+		}
+
+		for _, key := range reloadKeys(b.Name, b.Obj, changed) {
+			c.logger.Printf(`module %s reloading config %s`, b.Name, key)
+			if err := c.call(b.Name, PhaseConfigReload, func() error { return r.OnConfigReload(key, changed[key]) }); err != nil {
+				c.logFailure(b.Name, PhaseConfigReload, err)
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reloadKeys returns the changed keys module, bound under name, must be notified about, in a
+// stable order. A module that doesn't implement ConfigDependent only depends on the config
+// registered under its own name.
+func reloadKeys(name string, module any, changed map[string]any) []string {
+	d, ok := module.(ConfigDependent)
+	if !ok {
+		if _, ok := changed[name]; ok {
+			return []string{name}
+		}
+		return nil
+	}
+
+	var keys []string
+	for _, key := range d.RequiresConfig() {
+		if _, ok := changed[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// watchConfig reloads the global configs whenever the environment or a watched file
+// changes, until ctx, the container context, is cancelled.
+//
+// A change is only acted on once, so a reload that fails is not retried until the next change.
+func (c *container) watchConfig(ctx context.Context) {
+	last := c.configFingerprint()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.clock.After(c.configWatchInterval):
+		}
+
+		current := c.configFingerprint()
+		if current == last {
+			continue
+		}
+		last = current
+
+		c.logger.Println(`config changed, reloading`)
+		if err := c.ReloadConfig(); err != nil {
+			c.logFailure(``, PhaseConfigReload, err)
+		}
+	}
+}
+
+// configFingerprint hashes the environment and the size and modification time of the
+// watched files, so any change to them changes the fingerprint.
+func (c *container) configFingerprint() uint64 {
+	h := fnv.New64a()
+
+	env := os.Environ()
+	sort.Strings(env)
+	for _, kv := range env {
+		_, _ = fmt.Fprintln(h, kv)
+	}
+
+	for _, file := range c.configWatchFiles {
+		info, err := os.Stat(file)
+		if err != nil {
+			_, _ = fmt.Fprintln(h, file, `missing`)
+			continue
+		}
+		_, _ = fmt.Fprintln(h, file, info.Size(), info.ModTime().UnixNano())
+	}
+
+	return h.Sum64()
+}
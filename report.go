@@ -0,0 +1,107 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// ModuleReport summarizes the lifecycle interfaces a module implements, what it depends on
+// and the global configs it uses.
+type ModuleReport struct {
+	Name         string
+	State        ModuleState
+	Capabilities []string
+	DependsOn    []string
+	Configs      []string
+}
+
+// BootstrapReport is the report of every module, sorted by name.
+type BootstrapReport []ModuleReport
+
+// String renders the report as a table, for logging a startup summary.
+func (r BootstrapReport) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tSTATE\tCAPABILITIES\tDEPENDS ON\tCONFIGS")
+	for _, m := range r {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.Name, m.State, list(m.Capabilities), list(m.DependsOn), list(m.Configs))
+	}
+	_ = w.Flush()
+
+	return b.String()
+}
+
+// list joins names for a report cell, using a dash when there are none.
+func list(names []string) string {
+	if len(names) == 0 {
+		return `-`
+	}
+	return strings.Join(names, `, `)
+}
+
+// capabilities are the interfaces reported by Report, in the order they are listed.
+var capabilities = []struct {
+	name string
+	is   func(obj any) bool
+}{
+	{`configurable`, func(obj any) bool { _, ok := obj.(Configurable); return ok }},
+	{`initable`, func(obj any) bool { _, ok := obj.(Initable); return ok }},
+	{`runnable`, isRunnable},
+	{`stoppable`, isStoppable},
+	{`force-stoppable`, func(obj any) bool { _, ok := obj.(ForceStoppable); return ok }},
+	{`health`, func(obj any) bool { _, ok := obj.(HealthCheckable); return ok }},
+	{`liveness`, func(obj any) bool { _, ok := obj.(LivenessChecker); return ok }},
+	{`readiness`, func(obj any) bool { _, ok := obj.(ReadinessChecker); return ok }},
+	{`config-reloadable`, func(obj any) bool { _, ok := obj.(ConfigReloadable); return ok }},
+	{`startup-validator`, func(obj any) bool { _, ok := obj.(StartupValidator); return ok }},
+}
+
+// Report returns, for every module, the lifecycle interfaces it implements, its
+// dependencies and the global configs it uses, sorted by name.
+//
+// A module uses the config registered under its own name and the configs it lists with
+// RequiresConfig. Factory bindings are only included once they are constructed.
+func (c *container) Report() BootstrapReport {
+	report := make(BootstrapReport, 0)
+	for _, b := range c.walkBindings() {
+		m := ModuleReport{Name: b.Name, State: c.State(b.Name)}
+		for _, capability := range capabilities {
+			if capability.is(b.Obj) {
+				m.Capabilities = append(m.Capabilities, capability.name)
+			}
+		}
+		m.DependsOn = c.dependenciesOf(b.Name)
+
+		if _, ok := c.moduleConfig(b.Name); ok {
+			m.Configs = append(m.Configs, b.Name)
+		}
+		if d, ok := b.Obj.(ConfigDependent); ok {
+			for _, key := range d.RequiresConfig() {
+				if key != b.Name {
+					m.Configs = append(m.Configs, key)
+				}
+			}
+		}
+
+		report = append(report, m)
+	}
+	return report
+}
+
+// UninitializedInitables returns the names of the enabled modules implementing Initable or
+// InitableCtx that were never initialized, sorted by name. A module showing up here was
+// usually bound but left out of the Init call.
+//
+// Factory bindings are only included once they are constructed.
+func (c *container) UninitializedInitables() []string {
+	names := make([]string, 0)
+	for _, b := range c.walkBindings() {
+		_, initable := b.Obj.(Initable)
+		_, initableCtx := b.Obj.(InitableCtx)
+		if (initable || initableCtx) && c.State(b.Name) == StateRegistered {
+			names = append(names, b.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,93 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Restartable interface is used by modules that may not survive being stopped and started
+// again within the same process. Modules that don't implement it are restartable.
+type Restartable interface {
+	Restartable() bool
+}
+
+// RestartAll stops every module, reloads the global configs, and initializes and starts
+// again the modules that were initialized and started, reusing the same bindings.
+//
+// Start stays blocked throughout, so the process keeps running. Nothing is stopped if any
+// initialized module reports it is not Restartable or was bound with ManualShutdownOnly, or
+// if a config fails to load, as the configs are loaded and validated first. Modules are only
+// initialized again once the Run of every stopped module has returned.
+func (c *container) RestartAll() error {
+	c.lock.Lock()
+	initialized := append([]string{}, c.initialized...)
+	started := append([]string{}, c.started...)
+	c.lock.Unlock()
+
+	blocked := make([]string, 0)
+	for _, module := range unique(append(initialized, started...)) {
+		if c.options(module).manualShutdownOnly {
+			blocked = append(blocked, module)
+			continue
+		}
+		if r, ok := c.binding(module).(Restartable); ok && !r.Restartable() {
+			blocked = append(blocked, module)
+		}
+	}
+	if len(blocked) > 0 {
+		return fmt.Errorf(`container: modules [%s] are not restartable, restarting failed`, strings.Join(blocked, `, `))
+	}
+
+	// load and validate the configs first, so a bad config leaves everything running
+	changed, err := c.loadChangedConfigs(nil)
+	if err != nil {
+		return err
+	}
+
+	c.beginShutdown(ShutdownReason{Cause: CauseExplicit, Detail: `restart requested`})
+	if errs := c.stopAll(context.Background()); len(errs) > 0 {
+		return fmt.Errorf(`container: restarting failed: %w`, errors.Join(errs...))
+	}
+	if !c.waitRuns() {
+		return fmt.Errorf(`container: modules still running after %s, restarting failed`, c.stopTimeout)
+	}
+
+	c.lock.Lock()
+	c.resetModules()
+	c.lock.Unlock()
+
+	if len(changed) > 0 {
+		if err := c.swapConfigs(changed); err != nil {
+			return err
+		}
+	}
+	if err := c.InitE(initialized...); err != nil {
+		return err
+	}
+	err = c.launch(started)
+	c.endBootstrap(err)
+	if err != nil {
+		return err
+	}
+
+	// the shutdown cancelled the context the watchers were tied to
+	if len(started) > 0 && !c.stopRequested() {
+		c.startWatchers()
+	}
+	return nil
+}
+
+// unique returns names without duplicates, keeping the first occurrence of each.
+func unique(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
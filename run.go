@@ -0,0 +1,422 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ModuleList interface is used by configs that list the modules StartFromConfig starts.
+type ModuleList interface {
+	Modules() []string
+}
+
+// OneShot interface is used by runnable modules whose Run is expected to return right away,
+// such as migrations. They are exempt from the early exit check.
+type OneShot interface {
+	OneShot() bool
+}
+
+// StartupValidator interface is used by modules that check invariants across modules, such
+// as two modules pointing at the same region, after Init and before anything is started.
+type StartupValidator interface {
+	ValidateStartup(c Container) error
+}
+
+func (c *container) Start(modules ...string) {
+	if err := c.StartE(modules...); err != nil {
+		c.fail(err)
+	}
+}
+
+// StartE starts modules like Start but returns failures instead of panicking.
+//
+// Before any module is started, every module implementing StartupValidator is validated,
+// and the failures are returned together without starting anything. It blocks until the
+// container is stopped. When panics are recovered, a module whose Run fails stops the
+// container and its error is returned. If shutdown is requested while modules are still
+// being launched, the remaining modules are not started and are marked StateCancelled.
+func (c *container) StartE(modules ...string) error {
+	if err := c.validateStartup(); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.startedAt = c.clock.Now()
+	stopSigs := append([]<-chan any{}, c.stopSigs...)
+	allStopSigs := append([][]<-chan any{}, c.allStopSigs...)
+	c.lock.Unlock()
+	c.markBooted()
+
+	for _, sig := range stopSigs {
+		go func(ch <-chan any) {
+			sig := <-ch
+			// initiate graceful shutdown
+			c.beginShutdown(signalReason(sig))
+			c.signalStopped()
+		}(sig)
+	}
+
+	for _, chs := range allStopSigs {
+		go c.watchAllStopSignals(chs)
+	}
+
+	if c.signalShutdown {
+		source := c.signalSource
+		if source == nil {
+			source = newNotifySource(c.signals...)
+		}
+		go c.watchSignals(source)
+	}
+	go c.watchStopRequests()
+
+	launched := make(chan struct{})
+	if c.startupDeadline > 0 {
+		go c.watchStartup(modules, launched)
+	}
+
+	_, span := c.startSpan(c.bootstrapContext(context.Background()), `container.start`, attribute.StringSlice(`container.modules`, modules))
+	err := c.launch(modules)
+	endSpan(span, err)
+	c.endBootstrap(err)
+	close(launched)
+	if err != nil {
+		return err
+	}
+
+	if !c.stopRequested() {
+		c.startWatchers()
+		c.runStarted()
+	}
+
+	<-c.stopped
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.runErr
+}
+
+// launch starts the Run method of each module in dependency order without waiting for them
+// to return.
+//
+// A module is started once the modules it depends on among modules are started and ready,
+// so independent modules start concurrently while a ReadySignaler holds back only its own
+// dependents. Modules whose dependencies are ready start in dependency order, and launch
+// returns once every module is started and ready or won't be.
+func (c *container) launch(modules []string) error {
+	sorted, err := c.sortModules(modules)
+	if err != nil {
+		return err
+	}
+	if err := c.checkInitialized(sorted); err != nil {
+		return err
+	}
+
+	objs := make(map[string]any, len(sorted))
+	enabled := make([]string, 0, len(sorted))
+	for _, module := range sorted {
+		if c.isDisabled(module) {
+			c.logger.Printf(`module %s disabled, skipping start`, module)
+			continue
+		}
+
+		m, err := c.module(module)
+		if err != nil {
+			return err
+		}
+		if !isRunnable(m) {
+			return fmt.Errorf(`container: module [%s] is not runnable, starting failed`, module)
+		}
+		objs[module] = m
+		enabled = append(enabled, module)
+	}
+
+	l := newLauncher(c, enabled)
+	for _, module := range enabled {
+		l.add(module, objs[module])
+	}
+	l.wait()
+
+	return nil
+}
+
+// checkInitialized returns an error naming the enabled modules among modules that are not
+// initialized, so a module left out of Init, which may take a different list than Start,
+// is caught before anything starts.
+func (c *container) checkInitialized(modules []string) error {
+	missing := make([]string, 0)
+	for _, module := range modules {
+		if state := c.State(module); state != StateInitialized && !c.isDisabled(module) {
+			missing = append(missing, fmt.Sprintf(`%s (%s)`, module, state))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf(`container: modules [%s] are not initialized, starting failed`, strings.Join(missing, `, `))
+	}
+	return nil
+}
+
+// RunToCompletion starts modules like StartE, but instead of waiting for a stop it waits
+// for the Run of every module to return, which suits running the container as a batch job.
+//
+// Once all Runs have returned, every module is shut down with ShutdownAll. The Run
+// failures and the stop errors are returned together, also when panics are not recovered.
+// Run returning right away is expected here, so the early exit check is skipped.
+func (c *container) RunToCompletion(modules ...string) error {
+	if err := c.validateStartup(); err != nil {
+		return err
+	}
+
+	c.completing.Store(true)
+	defer c.completing.Store(false)
+
+	c.lock.Lock()
+	c.startedAt = c.clock.Now()
+	c.runErrs = nil
+	c.lock.Unlock()
+
+	if err := c.launch(modules); err != nil {
+		return err
+	}
+	c.running.Wait()
+
+	c.lock.Lock()
+	errs := append([]error{}, c.runErrs...)
+	c.lock.Unlock()
+
+	return errors.Join(append(errs, c.ShutdownAll())...)
+}
+
+// validateStartup runs ValidateStartup on every enabled module implementing StartupValidator
+// and returns the failures together.
+func (c *container) validateStartup() error {
+	var errs []error
+	for _, b := range c.walkBindings() {
+		v, ok := b.Obj.(StartupValidator)
+		if !ok || c.isDisabled(b.Name) {
+			continue
+		}
+		if err := c.call(b.Name, PhaseValidate, func() error { return v.ValidateStartup(c) }); err != nil {
+			errs = append(errs, fmt.Errorf(`container: module [%s] startup validation failed: %w`, b.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Uptime returns how long ago Start was called, or zero if the container hasn't been started.
+func (c *container) Uptime() time.Duration {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.startedAt.IsZero() {
+		return 0
+	}
+	return c.clock.Now().Sub(c.startedAt)
+}
+
+// markStarted records the module as started, unless shutdown has already been requested,
+// in which case it reports false.
+//
+// Checking and recording under the same lock means a concurrent shutdown either sees the
+// module as started and stops it, or the module is never started at all.
+func (c *container) markStarted(module string) bool {
+	c.lock.Lock()
+	if c.stopRequested() {
+		c.lock.Unlock()
+		return false
+	}
+
+	c.started = append(c.started, module)
+	c.states[module] = StateRunning
+	c.lock.Unlock()
+
+	c.emit(module, StateRunning, nil)
+	return true
+}
+
+// cancelStart marks modules that were not started because shutdown was requested during startup.
+func (c *container) cancelStart(modules []string) {
+	for _, module := range modules {
+		if c.isDisabled(module) {
+			continue
+		}
+		c.logger.Printf(`module %s cancelled, shutdown requested during startup`, module)
+		c.setState(module, StateCancelled)
+		c.emit(module, StateCancelled, nil)
+	}
+}
+
+// stopRequested reports whether shutdown has begun or the container has been stopped.
+func (c *container) stopRequested() bool {
+	if c.shuttingDown.Load() {
+		return true
+	}
+
+	select {
+	case <-c.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// StartFromConfig starts the modules listed in the global config registered under key.
+//
+// The config must implement ModuleList. Modules are started in dependency order, and an
+// error naming every listed module that isn't bound is returned before anything starts.
+func (c *container) StartFromConfig(key string) error {
+	cfg, ok := c.moduleConfig(key)
+	if !ok {
+		return fmt.Errorf(`container: config [%s] not found, starting failed`, key)
+	}
+
+	list, ok := cfg.(ModuleList)
+	if !ok {
+		return fmt.Errorf(`container: config [%s] is %T, not a module list`, key, cfg)
+	}
+	modules := list.Modules()
+
+	missing := make([]string, 0)
+	for _, module := range modules {
+		if !c.isBound(module) {
+			missing = append(missing, module)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf(`container: config [%s] lists unknown modules [%s]`, key, strings.Join(missing, `, `))
+	}
+
+	return c.StartE(modules...)
+}
+
+// RunMiddleware wraps the Run call of a module. It must call next to run the module
+// and should return the error next returns.
+type RunMiddleware func(module string, next func() error) error
+
+// UseRunMiddleware registers middleware wrapping the Run call of every module started afterwards.
+//
+// Middlewares compose in registration order: the first one registered is the outermost,
+// and the innermost call is the module's own Run.
+func (c *container) UseRunMiddleware(mw RunMiddleware) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.runMiddleware = append(c.runMiddleware, mw)
+}
+
+// wrapRun wraps run with the registered run middlewares.
+func (c *container) wrapRun(module string, run func() error) func() error {
+	c.lock.Lock()
+	mws := append([]RunMiddleware{}, c.runMiddleware...)
+	c.lock.Unlock()
+
+	for i := len(mws) - 1; i >= 0; i-- {
+		mw, next := mws[i], run
+		run = func() error {
+			return mw(module, next)
+		}
+	}
+	return run
+}
+
+// run calls Run on the module and handles its outcome.
+func (c *container) run(module string, r any) {
+	defer c.running.Done()
+
+	if o := c.options(module); o.restart != RestartNever {
+		c.supervise(module, r, o)
+		return
+	}
+
+	began := c.clock.Now()
+
+	err := c.call(module, PhaseRun, c.wrapRun(module, c.runFunc(module, r)))
+	racing := c.finishRace(module, err)
+	if err != nil {
+		c.markFailed(module, err)
+		// RunToCompletion and StartRace report the failure to their caller instead
+		if !c.recoverPanics && !c.completing.Load() && !racing {
+			panic(err)
+		}
+		c.runFailed(module, err)
+		return
+	}
+
+	if racing {
+		return
+	}
+	if err := c.checkEarlyExit(module, r, began); err != nil {
+		c.markFailed(module, err)
+		c.runFailed(module, err)
+	}
+}
+
+// isRunnable reports whether obj implements Runnable or RunnableCtx.
+func isRunnable(obj any) bool {
+	_, runnable := obj.(Runnable)
+	_, runnableCtx := obj.(RunnableCtx)
+	return runnable || runnableCtx
+}
+
+// runFunc returns the call running the module, preferring RunContext with the module's context
+// over Run. A context.Canceled error returned once that context is done is not a failure.
+func (c *container) runFunc(module string, r any) func() error {
+	if rc, ok := r.(RunnableCtx); ok {
+		ctx := c.ModuleContext(module)
+		return func() error {
+			err := rc.RunContext(ctx)
+			if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+	}
+	return r.(Runnable).Run
+}
+
+// checkEarlyExit warns about a long-lived module whose Run returned within the early exit window,
+// which usually means it failed silently. An error is returned if early exits are failures.
+func (c *container) checkEarlyExit(module string, r any, began time.Time) error {
+	if c.earlyExitWindow <= 0 || c.IsShuttingDown() || c.completing.Load() {
+		return nil
+	}
+	if c.ModuleContext(module).Err() != nil {
+		// the module was stopped on its own, for example by StopModule
+		return nil
+	}
+	if o, ok := r.(OneShot); ok && o.OneShot() {
+		return nil
+	}
+
+	elapsed := c.clock.Now().Sub(began)
+	if elapsed >= c.earlyExitWindow {
+		return nil
+	}
+
+	c.logWarning(module, `module %s returned from Run after %s, it may have failed to start`, module, elapsed)
+
+	if !c.earlyExitFails {
+		return nil
+	}
+	return fmt.Errorf(`container: module [%s] returned from Run after %s`, module, elapsed)
+}
+
+// runFailed records the first Run failure and stops the container.
+func (c *container) runFailed(module string, err error) {
+	c.logFailure(module, PhaseRun, err)
+	c.beginShutdown(ShutdownReason{Cause: CauseModule, Detail: fmt.Sprintf(`module %s failed`, module)})
+
+	c.lock.Lock()
+	if c.runErr == nil {
+		c.runErr = err
+	}
+	c.runErrs = append(c.runErrs, err)
+	c.lock.Unlock()
+
+	c.signalStopped()
+}
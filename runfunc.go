@@ -0,0 +1,89 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// RunFunc binds a module under name that runs fn until the module is stopped.
+//
+// The context passed to fn is derived from the module's context, so it is cancelled when
+// the module is stopped or shutdown begins. Stop cancels it and waits for fn to return.
+// A context.Canceled error returned by fn after stop is not treated as a failure.
+func (c *container) RunFunc(name string, fn func(ctx context.Context) error) {
+	c.Bind(name, &funcModule{name: name, fn: fn})
+}
+
+// RunFuncWithRestarts binds a module like RunFunc that restarts fn when it returns an
+// error, at most maxRestarts times, each time with a fresh context.
+//
+// The module is supervised like one bound with Restart(RestartOnFailure, maxRestarts) and
+// Critical, so restarts back off exponentially and the container shuts down once the budget
+// is spent. Shutdown always wins over a pending restart: fn is not run again once shutdown
+// has begun or the module's context is cancelled.
+func (c *container) RunFuncWithRestarts(name string, maxRestarts int, fn func(ctx context.Context) error) {
+	c.BindWithOptions(name, &funcModule{name: name, fn: fn}, Restart(RestartOnFailure, maxRestarts), Critical())
+}
+
+// funcModule is the module bound by RunFunc.
+type funcModule struct {
+	name string
+	fn   func(ctx context.Context) error
+
+	lock   sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (m *funcModule) Init(c Container) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.ctx, m.cancel = context.WithCancel(c.ModuleContext(m.name))
+	m.done = nil
+
+	return nil
+}
+
+func (m *funcModule) Run() error {
+	m.lock.Lock()
+	if m.ctx == nil {
+		m.ctx, m.cancel = context.WithCancel(context.Background())
+	}
+	ctx, done := m.ctx, make(chan struct{})
+	m.done = done
+	m.lock.Unlock()
+
+	defer close(done)
+
+	err := m.attempt(ctx)
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// attempt runs fn once with a fresh context derived from ctx, so every restart by the
+// supervisor gets its own.
+func (m *funcModule) attempt(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	return m.fn(ctx)
+}
+
+func (m *funcModule) Stop() error {
+	m.lock.Lock()
+	cancel, done := m.cancel, m.done
+	m.lock.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+	return nil
+}
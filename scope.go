@@ -0,0 +1,66 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// Scope is a child container for work with a shorter lifetime than the application, such
+// as a request. Closing it releases the scope without affecting its parent.
+type Scope interface {
+	Container
+	io.Closer
+}
+
+// Scope creates a child container of c.
+//
+// Names not bound in the scope resolve from c, as do global configs not set in the scope,
+// so the scope can override bindings of c without changing them. Objects resolved from c
+// are constructed and owned by c, and are not stopped when the scope is closed.
+//
+// The scope's Context is derived from the context of c, so shutting down c, or resetting
+// it, cancels the contexts of all its scopes, while closing a scope cancels only its own.
+// The scope logs like c and shares its clock, tracer and key normalizer.
+func (c *container) Scope() Scope {
+	s := NewContainer().(*container)
+	s.parent = c
+	s.logger = c.logger
+	s.clock = c.clock
+	s.tracer = c.tracer
+	s.keyNormalizer = c.keyNormalizer
+	s.recoverPanics = c.recoverPanics
+	s.errorHandler = c.errorHandler
+	s.strictResolve = c.strictResolve
+
+	s.lock.Lock()
+	s.resetContexts()
+	s.lock.Unlock()
+
+	return s
+}
+
+// closeScope cancels the context of a scope and disposes of the stoppable objects bound in
+// it. The initialized and started modules are stopped in shutdown order, followed by the
+// other stoppable bindings in name order. Stop errors are returned together.
+func (c *container) closeScope() error {
+	c.lock.Lock()
+	c.shuttingDown.Store(true)
+	c.cancel()
+	c.lock.Unlock()
+
+	errs := c.stopAll(context.Background())
+	for _, b := range c.walkBindings() {
+		if !isStoppable(b.Obj) || c.isDisabled(b.Name) || c.State(b.Name) != StateRegistered {
+			continue
+		}
+		if err := c.call(b.Name, PhaseStop, func() error { return c.stop(b.Name) }); err != nil {
+			c.logFailure(b.Name, PhaseStop, err)
+			errs = append(errs, err)
+		}
+	}
+
+	c.signalStopped()
+
+	return errors.Join(errs...)
+}
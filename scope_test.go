@@ -0,0 +1,36 @@
+package container
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShutdownCancelsScopeContexts(t *testing.T) {
+	c := NewContainer()
+	scopes := []Scope{c.Scope(), c.Scope(), c.Scope()}
+
+	closed := scopes[0]
+	if err := closed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Context().Err() != nil {
+		t.Fatal(`closing a scope cancelled the parent context`)
+	}
+	for _, s := range scopes[1:] {
+		if s.Context().Err() != nil {
+			t.Fatal(`closing a scope cancelled a sibling scope context`)
+		}
+	}
+
+	if err := c.ShutdownAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, s := range scopes {
+		select {
+		case <-s.Context().Done():
+		case <-time.After(time.Second):
+			t.Fatalf(`scope %d context not done after the parent shut down`, i)
+		}
+	}
+}
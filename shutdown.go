@@ -0,0 +1,397 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ShutdownAll gracefully shuts down every started module in the reverse order they were started,
+// or in the order set with WithShutdownOrder.
+//
+// Modules that were initialized but never started are stopped afterwards in reverse init
+// order, so their resources are released even when shutdown runs before Start. Stop errors
+// are logged and returned together. Once all modules are stopped the OnShutdownComplete
+// callbacks and the finalizers registered with BindWithFinalizer run, the event channels
+// are closed, the logger is flushed if its writer supports it and the container is marked
+// as stopped, so Start returns.
+func (c *container) ShutdownAll() error {
+	c.beginShutdown(explicitReason)
+
+	if c.strict {
+		c.logNeverStarted()
+	}
+
+	ctx, span := c.startSpan(context.Background(), `container.shutdown`, attribute.String(`container.shutdown.reason`, c.ShutdownReason().String()))
+	errs := c.stopAll(ctx)
+	endSpan(span, errors.Join(errs...))
+
+	if c.reportUnresolved {
+		c.logUnresolved()
+	}
+
+	c.runShutdownComplete()
+	c.runFinalizers()
+	c.closeEvents()
+	c.flushLogger()
+	c.signalStopped()
+
+	return errors.Join(errs...)
+}
+
+// stopAll stops every started or initialized module in shutdown order, except modules bound
+// with ManualShutdownOnly, and returns the stop errors.
+func (c *container) stopAll(ctx context.Context) []error {
+	pending := make([]string, 0)
+	for _, module := range c.shutdownOrder() {
+		if c.isDisabled(module) {
+			c.logger.Printf(`module %s disabled, skipping stop`, module)
+			continue
+		}
+		if c.options(module).manualShutdownOnly {
+			c.logger.Printf(`module %s is manual shutdown only, skipping stop`, module)
+			continue
+		}
+		if !isStoppable(c.binding(module)) {
+			continue
+		}
+		if state := c.State(module); state != StateRunning && state != StateInitialized {
+			c.logger.Printf(`module %s is %s, skipping stop`, module, state)
+			continue
+		}
+		pending = append(pending, module)
+	}
+
+	var errs []error
+	for i, module := range pending {
+		c.shutdownProgress(i, len(pending), module)
+
+		c.logger.Printf(`module %s stopping...`, module)
+
+		_, span := c.startSpan(ctx, `container.stop.module`, attribute.String(moduleAttr, module))
+		err := c.call(module, PhaseStop, func() error { return c.stop(module) })
+		endSpan(span, err)
+		if err != nil {
+			c.logFailure(module, PhaseStop, err)
+			errs = append(errs, err)
+		}
+
+		c.logger.Printf(`module %s stopped`, module)
+	}
+
+	return errs
+}
+
+// Shutdown gracefully shuts down modules in the order they are provided.
+func (c *container) Shutdown(modules ...string) {
+	_ = c.ShutdownE(modules...)
+}
+
+// ShutdownE shuts down modules like Shutdown and also returns the stop errors together.
+//
+// It is safe to call in any state: modules that were never initialized or started, or are
+// already stopped, are skipped.
+func (c *container) ShutdownE(modules ...string) error {
+	c.beginShutdown(explicitReason)
+	modules = c.keys(modules)
+
+	var errs []error
+	for _, module := range modules {
+		if c.isDisabled(module) {
+			c.logger.Printf(`module %s disabled, skipping stop`, module)
+			continue
+		}
+		if state := c.State(module); state == StateRegistered || state == StateStopped || state == StateCancelled {
+			c.logger.Printf(`module %s is %s, skipping stop`, module, state)
+			continue
+		}
+
+		c.logger.Printf(`module %s stopping...`, module)
+
+		if err := c.call(module, PhaseStop, func() error { return c.stop(module) }); err != nil {
+			c.logFailure(module, PhaseStop, err)
+			errs = append(errs, err)
+		}
+
+		c.logger.Printf(`module %s stopped`, module)
+	}
+
+	if c.reportUnresolved {
+		c.logUnresolved()
+	}
+
+	c.signalStopped()
+
+	return errors.Join(errs...)
+}
+
+// StopModule stops a single running or initialized module while the rest of the container
+// keeps running. The module's context is cancelled right before its Stop is called.
+func (c *container) StopModule(name string) error {
+	name = c.key(name)
+	if !isStoppable(c.binding(name)) {
+		return fmt.Errorf(`container: module [%s] is not stoppable, stopping failed`, name)
+	}
+	if state := c.State(name); state != StateRunning && state != StateInitialized {
+		return fmt.Errorf(`container: module [%s] is %s, stopping failed`, name, state)
+	}
+
+	c.logger.Printf(`module %s stopping...`, name)
+
+	if err := c.call(name, PhaseStop, func() error { return c.stop(name) }); err != nil {
+		c.logFailure(name, PhaseStop, err)
+		return err
+	}
+
+	c.logger.Printf(`module %s stopped`, name)
+
+	return nil
+}
+
+// ShutdownBefore shuts down modules in the order they are provided so that the whole teardown
+// finishes before deadline.
+//
+// Each module gets an equal share of the time remaining when its turn comes. Modules that
+// overrun their share are abandoned and reported in the returned error. Like ShutdownE it
+// is safe to call in any state: modules that aren't stoppable, were never initialized or
+// are already stopped are skipped and get no share of the time.
+func (c *container) ShutdownBefore(deadline time.Time, modules ...string) error {
+	c.beginShutdown(explicitReason)
+
+	pending := make([]string, 0, len(modules))
+	for _, module := range c.keys(modules) {
+		if c.isDisabled(module) {
+			c.logger.Printf(`module %s disabled, skipping stop`, module)
+			continue
+		}
+		if !isStoppable(c.binding(module)) {
+			continue
+		}
+		if state := c.State(module); state != StateRunning && state != StateInitialized {
+			c.logger.Printf(`module %s is %s, skipping stop`, module, state)
+			continue
+		}
+		pending = append(pending, module)
+	}
+
+	var errs []error
+	overran := make([]string, 0)
+	for i, module := range pending {
+		budget := deadline.Sub(c.clock.Now()) / time.Duration(len(pending)-i)
+		if budget <= 0 {
+			c.logWarning(module, `module %s not stopped, shutdown deadline passed`, module)
+			overran = append(overran, module)
+			continue
+		}
+
+		c.logger.Printf(`module %s stopping within %s...`, module, budget)
+
+		err := c.call(module, PhaseStop, func() error { return c.stopTimed(module, budget, 0) })
+		if err != nil {
+			c.logFailure(module, PhaseStop, err)
+			if errors.Is(err, ErrStopTimeout) {
+				overran = append(overran, module)
+				continue
+			}
+			errs = append(errs, err)
+		}
+
+		c.logger.Printf(`module %s stopped`, module)
+	}
+
+	if len(overran) > 0 {
+		errs = append(errs, fmt.Errorf(`%w, modules [%s] overran the shutdown deadline`, ErrStopTimeout, strings.Join(overran, `, `)))
+	}
+
+	c.signalStopped()
+
+	return errors.Join(errs...)
+}
+
+// Close shuts down all started modules with ShutdownAll, so the container can be used as an io.Closer.
+// Closing a scope created with Scope releases the scope instead.
+func (c *container) Close() error {
+	if c.parent != nil {
+		return c.closeScope()
+	}
+	return c.ShutdownAll()
+}
+
+// shutdownOrder returns the modules in the order ShutdownAll stops them.
+//
+// Modules listed with WithShutdownOrder come first, followed by the remaining started
+// modules in reverse start order and then the initialized modules in reverse init order.
+func (c *container) shutdownOrder() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	pending := make(map[string]bool, len(c.started)+len(c.initialized))
+	for _, module := range c.started {
+		pending[module] = true
+	}
+	for _, module := range c.initialized {
+		pending[module] = true
+	}
+
+	order := make([]string, 0, len(pending))
+	add := func(module string) {
+		if pending[module] {
+			order = append(order, module)
+			pending[module] = false
+		}
+	}
+
+	for _, module := range c.stopOrder {
+		add(module)
+	}
+	for i := len(c.started) - 1; i >= 0; i-- {
+		add(c.started[i])
+	}
+	for i := len(c.initialized) - 1; i >= 0; i-- {
+		add(c.initialized[i])
+	}
+
+	if c.observeDeps && len(c.stopOrder) == 0 {
+		return c.observedOrder(order)
+	}
+	return order
+}
+
+// logNeverStarted warns about stoppable modules that were never started, which usually
+// means they were left out of the Start call.
+func (c *container) logNeverStarted() {
+	c.lock.Lock()
+	names := make([]string, 0)
+	for _, name := range c.store.Keys() {
+		obj, _ := c.store.Get(name)
+		if !isStoppable(constructed(obj)) || c.isSelf(name) {
+			continue
+		}
+		if state := c.states[name]; state == StateRegistered || state == StateInitialized {
+			names = append(names, name)
+		}
+	}
+	c.lock.Unlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		c.logWarning(name, `module %s is stoppable but was never started`, name)
+	}
+}
+
+// signalStopped marks the container as stopped, releasing Start. It is safe to call more than once.
+func (c *container) signalStopped() {
+	c.stopOnce.Do(func() {
+		close(c.stopped)
+	})
+}
+
+// flushLogger flushes the logger output if the underlying writer buffers it.
+func (c *container) flushLogger() {
+	switch w := c.logger.Writer().(type) {
+	case interface{ Flush() error }:
+		_ = w.Flush()
+	case interface{ Sync() error }:
+		_ = w.Sync()
+	}
+}
+
+// stop stops a single module and records its resulting state.
+func (c *container) stop(module string) error {
+	timeout := c.stopTimeout
+	if d := c.options(module).stopTimeout; d > 0 {
+		timeout = d
+	}
+	return c.stopTimed(module, timeout, c.stopGracePeriod)
+}
+
+// isStoppable reports whether obj implements Stoppable or StoppableCtx.
+func isStoppable(obj any) bool {
+	_, stoppable := obj.(Stoppable)
+	_, stoppableCtx := obj.(StoppableCtx)
+	return stoppable || stoppableCtx
+}
+
+// stopTimed stops a single module with the given stop timeout and grace period and records
+// its resulting state.
+func (c *container) stopTimed(module string, timeout, grace time.Duration) error {
+	m, err := c.module(module)
+	if err != nil {
+		return err
+	}
+
+	if !isStoppable(m) {
+		panic(fmt.Sprintf(`container: module [%s] is not stoppable, stopping failed`, module))
+	}
+
+	if n, ok := m.(ShutdownNotifiable); ok {
+		n.OnShutdown(c.ShutdownReason())
+	}
+	c.cancelModule(module)
+
+	began := c.clock.Now()
+	if err := c.stopWithin(module, m, timeout, grace); err != nil {
+		c.markFailed(module, err)
+		return err
+	}
+
+	c.setState(module, StateStopped)
+	c.publish(Event{Module: module, State: StateStopped, Duration: c.clock.Now().Sub(began)})
+	return nil
+}
+
+// stopWithin calls StopContext or Stop on the module, giving up on it once the timeout and
+// grace period have passed. A zero timeout waits indefinitely.
+func (c *container) stopWithin(module string, m any, timeout, grace time.Duration) error {
+	stop := func() error {
+		if s, ok := m.(StoppableCtx); ok {
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = c.withTimeout(ctx, timeout+grace)
+				defer cancel()
+			}
+			return s.StopContext(ctx)
+		}
+		return m.(Stoppable).Stop()
+	}
+
+	if timeout <= 0 {
+		return stop()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.call(module, PhaseStop, stop)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-c.clock.After(timeout):
+	}
+
+	c.logWarning(module, `module %s did not stop within %s`, module, timeout)
+
+	if grace > 0 {
+		select {
+		case err := <-done:
+			return err
+		case <-c.clock.After(grace):
+		}
+	}
+
+	if f, ok := m.(ForceStoppable); ok {
+		c.logger.Printf(`module %s force stopping...`, module)
+		if err := c.call(module, PhaseForceStop, f.ForceStop); err != nil {
+			return fmt.Errorf(`container: module [%s] force stop failed: %w`, module, err)
+		}
+	}
+
+	return fmt.Errorf(`%w, module [%s] abandoned`, ErrStopTimeout, module)
+}
@@ -0,0 +1,47 @@
+package container
+
+import (
+	"testing"
+	"time"
+)
+
+type countingStopper struct {
+	stops int
+}
+
+func (m *countingStopper) Init(Container) error { return nil }
+
+func (m *countingStopper) Stop() error {
+	m.stops++
+	return nil
+}
+
+func TestShutdownBeforeStopsOnce(t *testing.T) {
+	c := NewContainer()
+	m := &countingStopper{}
+	c.Bind(`db`, m)
+	c.Init(`db`)
+
+	for range 2 {
+		if err := c.ShutdownBefore(time.Now().Add(time.Second), `db`); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m.stops != 1 {
+		t.Fatalf(`expected db to be stopped once, got %d`, m.stops)
+	}
+}
+
+func TestShutdownBeforeSkipsUnstoppableModules(t *testing.T) {
+	c := NewContainer()
+	c.Bind(`config`, orderModule{})
+	c.Bind(`db`, &countingStopper{})
+	c.Init(`config`, `db`)
+
+	if err := c.ShutdownBefore(time.Now().Add(time.Second), `config`, `db`, `cache`); err != nil {
+		t.Fatal(err)
+	}
+	if state := c.State(`db`); state != StateStopped {
+		t.Fatalf(`expected db to be stopped, got %s`, state)
+	}
+}
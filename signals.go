@@ -0,0 +1,141 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// SignalSource delivers the OS signals that trigger a graceful shutdown.
+type SignalSource interface {
+	// Signals returns the channel the signals are delivered on.
+	Signals() <-chan os.Signal
+	// Stop stops delivering signals.
+	Stop()
+}
+
+// SignalChan is a SignalSource backed by a plain channel, so tests can push a synthetic
+// signal such as syscall.SIGTERM without involving the OS.
+type SignalChan chan os.Signal
+
+func (s SignalChan) Signals() <-chan os.Signal {
+	return s
+}
+
+func (s SignalChan) Stop() {}
+
+// notifySource is the default SignalSource, receiving signals with signal.Notify.
+type notifySource struct {
+	ch chan os.Signal
+}
+
+// newNotifySource returns a source receiving sigs, or SIGINT and SIGTERM if none are given.
+func newNotifySource(sigs ...os.Signal) *notifySource {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	s := &notifySource{ch: make(chan os.Signal, 1)}
+	signal.Notify(s.ch, sigs...)
+	return s
+}
+
+func (s *notifySource) Signals() <-chan os.Signal {
+	return s.ch
+}
+
+func (s *notifySource) Stop() {
+	signal.Stop(s.ch)
+}
+
+// watchSignals shuts down every module with ShutdownAll once a signal arrives from source,
+// and stops watching when the container is stopped.
+func (c *container) watchSignals(source SignalSource) {
+	defer source.Stop()
+
+	select {
+	case sig := <-source.Signals():
+		c.beginShutdown(signalReason(sig))
+		_ = c.ShutdownAll()
+	case <-c.stopped:
+	}
+}
+
+// StopChannel returns a channel modules can send on to request a graceful shutdown. While
+// Start is running, a value received on it shuts every module down with ShutdownAll, like a
+// signal does. A value that is a string, error or fmt.Stringer is reported as the shutdown reason.
+func (c *container) StopChannel() chan<- any {
+	return c.stopRequests
+}
+
+// NotifyStop requests a graceful shutdown through StopChannel without blocking. Further
+// requests while one is pending are dropped.
+func (c *container) NotifyStop() {
+	select {
+	case c.stopRequests <- nil:
+	default:
+	}
+}
+
+// watchStopRequests shuts down every module with ShutdownAll once a stop is requested, and
+// stops watching when the container is stopped.
+func (c *container) watchStopRequests() {
+	select {
+	case req := <-c.stopRequests:
+		c.beginShutdown(stopRequestReason(req))
+		_ = c.ShutdownAll()
+	case <-c.stopped:
+	}
+}
+
+// stopRequestReason describes a stop requested through StopChannel.
+func stopRequestReason(req any) ShutdownReason {
+	reason := ShutdownReason{Cause: CauseExplicit, Detail: `stop requested`}
+	switch r := req.(type) {
+	case string:
+		reason.Detail = r
+	case error:
+		reason.Detail = r.Error()
+	case fmt.Stringer:
+		reason.Detail = r.String()
+	}
+	return reason
+}
+
+// RegisterStopSignal registers channels that each trigger a graceful shutdown as soon as
+// any of them delivers a value. Channels must be registered before Start.
+func (c *container) RegisterStopSignal(chs ...<-chan any) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.stopSigs = append(c.stopSigs, chs...)
+}
+
+// RegisterAllStopSignal registers a set of channels that triggers a graceful shutdown only
+// once every one of them has delivered a value, such as drain sources that must all report
+// done. Each call registers an independent set. Channels must be registered before Start.
+func (c *container) RegisterAllStopSignal(chs ...<-chan any) {
+	if len(chs) == 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.allStopSigs = append(c.allStopSigs, chs)
+}
+
+// watchAllStopSignals waits for every channel of chs and then begins the shutdown.
+func (c *container) watchAllStopSignals(chs []<-chan any) {
+	for _, ch := range chs {
+		select {
+		case <-ch:
+		case <-c.stopped:
+			return
+		}
+	}
+
+	c.beginShutdown(ShutdownReason{Cause: CauseSignal, Detail: fmt.Sprintf(`all %d stop signals fired`, len(chs))})
+	c.signalStopped()
+}
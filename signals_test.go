@@ -0,0 +1,41 @@
+package container
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegisterAllStopSignalWaitsForEveryChannel(t *testing.T) {
+	c := NewContainer()
+	chs := []chan any{make(chan any, 1), make(chan any, 1), make(chan any, 1)}
+	c.RegisterAllStopSignal(chs[0], chs[1], chs[2])
+
+	done := make(chan error, 1)
+	go func() { done <- c.StartE() }()
+
+	chs[0] <- struct{}{}
+	chs[2] <- struct{}{}
+
+	select {
+	case <-done:
+		t.Fatal(`shut down before every stop signal fired`)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if c.IsShuttingDown() {
+		t.Fatal(`shutdown began before every stop signal fired`)
+	}
+
+	chs[1] <- struct{}{}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal(`still running after every stop signal fired`)
+	}
+	if !c.IsShuttingDown() {
+		t.Fatal(`expected shutdown to have begun`)
+	}
+}
@@ -0,0 +1,121 @@
+package container
+
+import "reflect"
+
+// maskedValue replaces string fields reported by SensitiveConfig in a config snapshot.
+const maskedValue = `******`
+
+// SensitiveConfig interface is used by configs with fields that must not leak into a
+// ConfigSnapshot, such as passwords. Sensitive returns the names of those struct fields.
+type SensitiveConfig interface {
+	Sensitive() []string
+}
+
+// ConfigSnapshot returns a deep copy of every global config, keyed like the configs, for
+// capturing the effective config in a crash report.
+//
+// The copies share no exported references with the live configs, so they can be serialized
+// while the configs are reloaded. Fields named by SensitiveConfig are masked: strings are
+// replaced with a placeholder and other values are zeroed.
+func (c *container) ConfigSnapshot() map[string]any {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	snapshot := make(map[string]any, len(c.moduleConfigs))
+	for key, cfg := range c.moduleConfigs {
+		cp := deepCopy(reflect.ValueOf(cfg))
+		if s, ok := cfg.(SensitiveConfig); ok {
+			mask(cp, s.Sensitive())
+		}
+		snapshot[key] = cp.Interface()
+	}
+	return snapshot
+}
+
+// deepCopy returns a copy of v that shares no pointers, slices or maps reachable through
+// exported fields with v. Unexported fields are copied as they are.
+func deepCopy(v reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type().Elem())
+		cp.Elem().Set(deepCopy(v.Elem()))
+		return cp
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(deepCopy(v.Elem()))
+		return cp
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				cp.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return cp
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+	case reflect.Array:
+		cp := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+		}
+		return cp
+	default:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		return cp
+	}
+}
+
+// mask blanks the named fields of the struct v holds or points to.
+func mask(v reflect.Value, fields []string) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, name := range fields {
+		f := v.FieldByName(name)
+		if !f.IsValid() || !f.CanSet() {
+			continue
+		}
+		if f.Kind() == reflect.String {
+			f.SetString(maskedValue)
+			continue
+		}
+		f.SetZero()
+	}
+}
@@ -0,0 +1,119 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// startupPollInterval is how often the startup watchdog polls readiness once every module is launched.
+const startupPollInterval = 100 * time.Millisecond
+
+// markBooted records the start of the startup, unless it was already recorded.
+func (c *container) markBooted() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.bootedAt.IsZero() {
+		c.bootedAt = c.clock.Now()
+	}
+}
+
+// watchStartup shuts the container down if modules are not launched and ready before the
+// startup deadline. launched is closed once Start has launched every module.
+func (c *container) watchStartup(modules []string, launched <-chan struct{}) {
+	c.lock.Lock()
+	deadline := c.bootedAt.Add(c.startupDeadline)
+	c.lock.Unlock()
+
+	timeout := c.clock.After(deadline.Sub(c.clock.Now()))
+
+	select {
+	case <-launched:
+	case <-timeout:
+		c.startupTimedOut(c.notRunning(modules))
+		return
+	case <-c.stopped:
+		return
+	}
+
+	for {
+		pending := c.unready(modules, deadline)
+		if len(pending) == 0 {
+			return
+		}
+
+		select {
+		case <-c.clock.After(startupPollInterval):
+		case <-timeout:
+			c.startupTimedOut(pending)
+			return
+		case <-c.stopped:
+			return
+		}
+	}
+}
+
+// notRunning returns the enabled modules that are not running yet.
+func (c *container) notRunning(modules []string) []string {
+	pending := make([]string, 0)
+	for _, module := range modules {
+		if !c.isDisabled(module) && !c.IsRunning(module) {
+			pending = append(pending, module)
+		}
+	}
+	return pending
+}
+
+// unready returns the enabled modules that are not running yet or whose readiness check fails.
+func (c *container) unready(modules []string, deadline time.Time) []string {
+	pending := c.notRunning(modules)
+
+	ctx, cancel := c.withDeadline(c.Context(), deadline)
+	defer cancel()
+
+	for _, module := range modules {
+		r, ok := c.binding(module).(ReadinessChecker)
+		if !ok || !c.IsRunning(module) || c.options(module).skipHealthCheck {
+			continue
+		}
+		if err := c.call(module, PhaseHealthCheck, func() error { return r.ReadinessCheck(ctx) }); err != nil {
+			pending = append(pending, module)
+		}
+	}
+	return pending
+}
+
+// initTimedOut returns the failure of an init of modules aborted by the startup deadline,
+// naming the modules that were not initialized, and logs it.
+func (c *container) initTimedOut(modules, done []string, err error) error {
+	initialized := make(map[string]bool, len(done))
+	for _, name := range done {
+		initialized[name] = true
+	}
+	pending := make([]string, 0)
+	for _, name := range c.keys(modules) {
+		if !initialized[name] {
+			pending = append(pending, name)
+		}
+	}
+
+	err = fmt.Errorf(`%w after %s, modules [%s] still initializing: %w`, ErrStartupTimeout, c.startupDeadline, strings.Join(pending, `, `), err)
+	c.logFailure(``, PhaseInit, err)
+	return err
+}
+
+// startupTimedOut records the startup timeout as the Start failure and shuts every module down.
+func (c *container) startupTimedOut(pending []string) {
+	err := fmt.Errorf(`%w after %s, modules [%s] still pending`, ErrStartupTimeout, c.startupDeadline, strings.Join(pending, `, `))
+	c.logFailure(``, ``, err)
+
+	c.lock.Lock()
+	if c.runErr == nil {
+		c.runErr = err
+	}
+	c.lock.Unlock()
+
+	c.beginShutdown(ShutdownReason{Cause: CauseTimeout, Detail: `startup deadline exceeded`})
+	_ = c.ShutdownAll()
+}
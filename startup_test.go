@@ -0,0 +1,32 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type blockingInit struct{}
+
+func (blockingInit) InitContext(ctx context.Context, _ Container) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestStartupDeadlineAbortsInit(t *testing.T) {
+	c := NewContainer(WithStartupDeadline(50 * time.Millisecond))
+	c.Bind(`db`, blockingInit{})
+
+	done := make(chan error, 1)
+	go func() { done <- c.InitE(`db`) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrStartupTimeout) {
+			t.Fatalf(`expected ErrStartupTimeout, got %v`, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal(`init still running after the startup deadline`)
+	}
+}
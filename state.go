@@ -0,0 +1,90 @@
+package container
+
+// ModuleState describes where a module is in its lifecycle.
+type ModuleState int
+
+const (
+	// StateUnknown is reported for names nothing is bound to.
+	StateUnknown ModuleState = iota
+	StateRegistered
+	StateInitialized
+	StateRunning
+	StateStopped
+	StateFailed
+	StateDisabled
+	// StateCancelled is reported for modules whose start was abandoned because shutdown
+	// was requested during startup.
+	StateCancelled
+)
+
+func (s ModuleState) String() string {
+	switch s {
+	case StateRegistered:
+		return `registered`
+	case StateInitialized:
+		return `initialized`
+	case StateRunning:
+		return `running`
+	case StateStopped:
+		return `stopped`
+	case StateFailed:
+		return `failed`
+	case StateDisabled:
+		return `disabled`
+	case StateCancelled:
+		return `cancelled`
+	default:
+		return `unknown`
+	}
+}
+
+// State returns the lifecycle state of the named module.
+func (c *container) State(name string) ModuleState {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.states[c.key(name)]
+}
+
+// IsRunning reports whether the named module is running. It is false for unknown and
+// never-started modules.
+func (c *container) IsRunning(name string) bool {
+	return c.State(name) == StateRunning
+}
+
+// SetEnabled enables or disables the named module. Modules are enabled by default.
+//
+// Disabled modules stay bound but are skipped by Init, Start and shutdown, which lets
+// feature-flagged modules ship in the binary and be toggled per environment.
+func (c *container) SetEnabled(name string, enabled bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	name = c.key(name)
+	if !enabled {
+		c.disabled[name] = true
+		c.states[name] = StateDisabled
+		return
+	}
+
+	delete(c.disabled, name)
+	if c.states[name] == StateDisabled {
+		c.states[name] = StateRegistered
+	}
+}
+
+// setState records the lifecycle state of the named module.
+func (c *container) setState(name string, state ModuleState) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.states[name] = state
+}
+
+// isDisabled reports whether the named module was disabled with SetEnabled.
+func (c *container) isDisabled(name string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.disabled[c.key(name)]
+}
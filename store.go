@@ -0,0 +1,42 @@
+package container
+
+// Store holds the bindings of a container.
+//
+// The container guards its store with its own read-write lock: Get and Keys may run
+// concurrently with each other, but never with Set or Delete, as with a plain map. An
+// implementation only needs further synchronization if it is shared with other containers
+// or changed from outside the container.
+type Store interface {
+	// Get returns the object bound under name.
+	Get(name string) (any, bool)
+	// Set binds obj under name, replacing any previous binding.
+	Set(name string, obj any)
+	// Delete removes the binding under name.
+	Delete(name string)
+	// Keys returns the names of all bindings, in any order.
+	Keys() []string
+}
+
+// mapStore is the default in-memory Store.
+type mapStore map[string]any
+
+func (s mapStore) Get(name string) (any, bool) {
+	obj, ok := s[name]
+	return obj, ok
+}
+
+func (s mapStore) Set(name string, obj any) {
+	s[name] = obj
+}
+
+func (s mapStore) Delete(name string) {
+	delete(s, name)
+}
+
+func (s mapStore) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for name := range s {
+		keys = append(keys, name)
+	}
+	return keys
+}
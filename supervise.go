@@ -0,0 +1,149 @@
+package container
+
+import (
+	"fmt"
+	"time"
+)
+
+// RestartPolicy says when a supervised module's Run is restarted after it returns.
+type RestartPolicy int
+
+const (
+	// RestartNever leaves the module unsupervised, so a failing Run stops the container.
+	RestartNever RestartPolicy = iota
+	// RestartOnFailure restarts Run when it returns an error or panics.
+	RestartOnFailure
+	// RestartAlways restarts Run whenever it returns, unless the module is being stopped.
+	RestartAlways
+)
+
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartOnFailure:
+		return `on-failure`
+	case RestartAlways:
+		return `always`
+	default:
+		return `never`
+	}
+}
+
+const (
+	defaultRestartBackoff    = 100 * time.Millisecond
+	defaultMaxRestartBackoff = 30 * time.Second
+)
+
+// supervise runs a module bound with Restart, restarting its Run according to the policy
+// with exponential backoff. Panics in Run are recovered and count as failures, whether or
+// not WithRecover is set. Stopping the module or shutting down always wins over a restart.
+func (c *container) supervise(module string, r any, o bindOptions) {
+	delay, maxDelay := o.backoff, o.maxBackoff
+	if delay <= 0 {
+		delay = defaultRestartBackoff
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRestartBackoff
+	}
+	ctx := c.ModuleContext(module)
+
+	var err error
+	defer func() { c.finishRace(module, err) }()
+
+	for restarts := 0; ; restarts++ {
+		began := c.clock.Now()
+		err = c.recovered(module, PhaseRun, c.wrapRun(module, c.runFunc(module, r)))
+
+		if ctx.Err() != nil || c.stopRequested() {
+			if err != nil {
+				c.logFailure(module, PhaseRun, err)
+				c.markFailed(module, err)
+			}
+			return
+		}
+		if err == nil && o.restart == RestartOnFailure {
+			if err = c.checkEarlyExit(module, r, began); err == nil {
+				return
+			}
+		}
+
+		if o.maxRestarts >= 0 && restarts >= o.maxRestarts {
+			c.restartsExhausted(module, o, err)
+			return
+		}
+
+		if err != nil {
+			c.logWarning(module, `module %s failed, restarting in %s (%d/%s): %v`, module, delay, restarts+1, restartLimit(o.maxRestarts), err)
+		} else {
+			c.logger.Printf(`module %s returned from Run, restarting in %s (%d/%s)`, module, delay, restarts+1, restartLimit(o.maxRestarts))
+		}
+
+		select {
+		case <-c.clock.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		if c.stopRequested() {
+			return
+		}
+
+		c.lock.Lock()
+		c.restarts[module]++
+		n := c.restarts[module]
+		c.lock.Unlock()
+		c.publish(Event{Module: module, State: StateRunning, Err: err, Restarts: n})
+
+		if delay *= 2; delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// restartsExhausted marks a supervised module that exceeded its restart budget as failed,
+// shutting the container down if the module is critical.
+func (c *container) restartsExhausted(module string, o bindOptions, err error) {
+	if err != nil {
+		err = fmt.Errorf(`container: module [%s] exceeded %d restarts: %w`, module, o.maxRestarts, err)
+	} else {
+		err = fmt.Errorf(`container: module [%s] exceeded %d restarts`, module, o.maxRestarts)
+	}
+
+	c.markFailed(module, err)
+	if o.critical {
+		c.runFailed(module, err)
+		return
+	}
+	c.logFailure(module, PhaseRun, err)
+}
+
+// restartLimit formats a restart budget for the logs.
+func restartLimit(maxRestarts int) string {
+	if maxRestarts < 0 {
+		return `unlimited`
+	}
+	return fmt.Sprint(maxRestarts)
+}
+
+// recovered invokes fn on behalf of module like call, but always returns a panic as a
+// *LifecyclePanic instead of raising it again, so the supervisor can restart the module.
+func (c *container) recovered(module string, phase Phase, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = asLifecyclePanic(module, phase, r)
+		}
+	}()
+
+	return fn()
+}
+
+// RestartCounts returns how many times each supervised module has been restarted since the
+// container was created or last reset. Modules that were never restarted are left out.
+func (c *container) RestartCounts() map[string]int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	counts := make(map[string]int, len(c.restarts))
+	for name, n := range c.restarts {
+		counts[name] = n
+	}
+	return counts
+}
@@ -0,0 +1,60 @@
+package container
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// moduleAttr is the span attribute naming the module a span is about.
+const moduleAttr = `container.module`
+
+// startSpan starts a span on the tracer set with WithTracer, a no-op tracer by default.
+func (c *container) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// bootstrapContext returns ctx with the container.bootstrap span as the parent of the spans
+// started from it, starting that span on first use, so the init and start spans of a boot
+// share one parent. Once the bootstrap has ended, and in scopes, ctx is returned as it is.
+func (c *container) bootstrapContext(ctx context.Context) context.Context {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.bootEnded || c.parent != nil {
+		return ctx
+	}
+	if c.bootSpan == nil {
+		_, c.bootSpan = c.startSpan(ctx, `container.bootstrap`)
+	}
+	return trace.ContextWithSpan(ctx, c.bootSpan)
+}
+
+// endBootstrap ends the container.bootstrap span, once the modules are launched or the
+// boot failed.
+func (c *container) endBootstrap(err error) {
+	c.lock.Lock()
+	span := c.bootSpan
+	if c.bootEnded {
+		span = nil
+	}
+	c.bootEnded = true
+	c.lock.Unlock()
+
+	if span != nil {
+		endSpan(span, err)
+	}
+}
+
+// endSpan records the outcome of the traced work on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, ``)
+	}
+	span.End()
+}
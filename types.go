@@ -5,3 +5,9 @@ type ModuleConfig struct {
 	Key   string
 	Value any
 }
+
+// Binding represents an object to be bound under a name.
+type Binding struct {
+	Name string
+	Obj  any
+}
@@ -0,0 +1,20 @@
+package container
+
+// WithValue stores val under key on the container.
+//
+// Like context values, keys should be of an unexported type owned by the package that
+// defines them, so different packages never collide.
+func (c *container) WithValue(key, val any) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.values[key] = val
+}
+
+// Value returns the value stored under key, or nil if there is none.
+func (c *container) Value(key any) any {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.values[key]
+}